@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tradik/cv-xslt/scripts/tools/stats/internal/docker"
+)
+
+// actionTimeout bounds a container action; stopping waits for the grace
+// period before the daemon kills the container
+const actionTimeout = 30 * time.Second
+
+// pendingAction is a container action awaiting confirmation
+type pendingAction struct {
+	action docker.Action
+	id     string
+	name   string
+	signal int // Index into docker.KillSignals
+}
+
+type actionMsg struct {
+	action docker.Action
+	name   string
+	signal string
+	err    error
+}
+
+// runAction performs a confirmed container action
+func runAction(client *docker.Client, p pendingAction) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
+		defer cancel()
+
+		signal := ""
+		if p.action == docker.ActionKill {
+			signal = docker.KillSignals[p.signal]
+		}
+		err := client.RunAction(ctx, p.action, p.id, signal)
+		return actionMsg{action: p.action, name: p.name, signal: signal, err: err}
+	}
+}
+
+// requestAction asks for confirmation of the action bound to key on the
+// selected container
+func (m statsModel) requestAction(key string) (tea.Model, tea.Cmd) {
+	c, ok := m.selectedContainer()
+	if !ok {
+		return m, nil
+	}
+	if m.client.ReadOnly() {
+		m.status = redStyle.Render("✕ " + docker.ErrReadOnly.Error())
+		return m, nil
+	}
+
+	var action docker.Action
+	switch key {
+	case "S":
+		action = docker.StartStopAction(c.State)
+	case "P":
+		action = docker.PauseAction(c.State)
+	case "T":
+		action = docker.ActionRestart
+	case "K":
+		action = docker.ActionKill
+	}
+	m.confirm = &pendingAction{action: action, id: c.ID, name: c.Name}
+	return m, nil
+}
+
+// updateConfirm handles keys while an action awaits confirmation
+func (m statsModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.confirm
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+	case "y", "Y", "enter":
+		m.confirm = nil
+		m.status = dimStyle.Render(fmt.Sprintf("… %s %s", p.action, p.name))
+		return m, runAction(m.client, *p)
+	case "n", "N", "esc", "q":
+		m.confirm = nil
+	case "left", "h", "shift+tab":
+		if p.action == docker.ActionKill {
+			p.signal = (p.signal + len(docker.KillSignals) - 1) % len(docker.KillSignals)
+		}
+	case "right", "l", "tab":
+		if p.action == docker.ActionKill {
+			p.signal = (p.signal + 1) % len(docker.KillSignals)
+		}
+	}
+	return m, nil
+}
+
+// actionResult records the outcome of an action in the status line
+func (m statsModel) actionResult(msg actionMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.status = redStyle.Render("✕ " + msg.err.Error())
+		return m, nil
+	}
+	done := fmt.Sprintf("✓ %s %s", msg.action, msg.name)
+	if msg.signal != "" {
+		done += " (" + msg.signal + ")"
+	}
+	m.status = greenStyle.Render(done)
+	return m, fetchContainers(m.client, m.showAll)
+}
+
+// renderActionLine renders the confirmation prompt, or the action keys and
+// the outcome of the last action
+func (m statsModel) renderActionLine() string {
+	if p := m.confirm; p != nil {
+		verb := strings.ToUpper(p.action.String()[:1]) + p.action.String()[1:]
+		if p.action != docker.ActionKill {
+			return yellowStyle.Render(fmt.Sprintf("  %s container %s? ", verb, p.name)) +
+				cyanStyle.Render("[y]") + "es " + cyanStyle.Render("[n]") + "o"
+		}
+		s := yellowStyle.Render(fmt.Sprintf("  Kill container %s with ", p.name))
+		for i, signal := range docker.KillSignals {
+			if i == p.signal {
+				s += selectedStyle.Render(" "+signal+" ") + " "
+			} else {
+				s += dimStyle.Render(signal) + " "
+			}
+		}
+		return s + cyanStyle.Render("[←→]") + " signal " + cyanStyle.Render("[y]") + "es " + cyanStyle.Render("[n]") + "o"
+	}
+
+	var s string
+	if m.client.ReadOnly() {
+		s = dimStyle.Render("  │  ") + yellowStyle.Render("read-only")
+	} else {
+		s = dimStyle.Render("  │  ") + cyanStyle.Render("[S]") + "top/start " + cyanStyle.Render("[R]") + "estart " +
+			cyanStyle.Render("[P]") + "ause " + cyanStyle.Render("[K]") + "ill " + cyanStyle.Render("[e]") + "xec"
+	}
+	if m.status != "" {
+		s += dimStyle.Render("  │  ") + m.status
+	}
+	return s
+}
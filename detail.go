@@ -0,0 +1,381 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tradik/cv-xslt/scripts/tools/stats/internal/docker"
+)
+
+// detailTab identifies a tab of the full-screen detail view
+type detailTab int
+
+const (
+	tabMetrics detailTab = iota
+	tabInspect
+	tabHealth
+	tabProcesses
+)
+
+// detailTabs are the tab titles in display order
+var detailTabs = []string{"Metrics", "Inspect", "Health", "Processes"}
+
+// noHostStats explains missing %CPU and RSS columns in the process table
+const noHostStats = "%CPU and RSS are shown when running on the Docker host"
+
+// detailView is the state of the full-screen detail view opened with Enter
+type detailView struct {
+	open    bool
+	id      string
+	name    string
+	tab     detailTab
+	scroll  int
+	details docker.ContainerDetails
+	procs   docker.ProcessList
+	err     error // Inspect failed
+	procErr error // Top failed, e.g. the container is not running
+	loaded  bool
+}
+
+type detailMsg struct {
+	id      string
+	details docker.ContainerDetails
+	procs   docker.ProcessList
+	err     error
+	procErr error
+}
+
+// fetchDetail inspects a container and lists its processes
+func fetchDetail(client *docker.Client, id string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		details, err := client.GetContainerDetails(ctx, id)
+		procs, procErr := client.GetProcesses(ctx, id)
+		return detailMsg{id: id, details: details, procs: procs, err: err, procErr: procErr}
+	}
+}
+
+// openDetail opens the detail view for the selected container
+func (m statsModel) openDetail() (statsModel, tea.Cmd) {
+	c, ok := m.selectedContainer()
+	if !ok {
+		return m, nil
+	}
+	m.detail = detailView{open: true, id: c.ID, name: c.Name}
+	return m, fetchDetail(m.client, c.ID)
+}
+
+// updateDetail handles keys while the detail view is open. The table
+// selection is left untouched so Esc returns to the same row.
+func (m statsModel) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.detail = detailView{}
+	case "tab", "right":
+		m.detail.tab = (m.detail.tab + 1) % detailTab(len(detailTabs))
+		m.detail.scroll = 0
+	case "shift+tab", "left":
+		m.detail.tab = (m.detail.tab + detailTab(len(detailTabs)) - 1) % detailTab(len(detailTabs))
+		m.detail.scroll = 0
+	case "1", "2", "3", "4":
+		m.detail.tab = detailTab(msg.String()[0] - '1')
+		m.detail.scroll = 0
+	case "up", "k":
+		if m.detail.scroll > 0 {
+			m.detail.scroll--
+		}
+	case "down", "j":
+		m.detail.scroll++
+	case "pgup":
+		m.detail.scroll = max(m.detail.scroll-m.detailRows(), 0)
+	case "pgdown":
+		m.detail.scroll += m.detailRows()
+	case "home":
+		m.detail.scroll = 0
+	case "r":
+		return m, fetchDetail(m.client, m.detail.id)
+	}
+	return m, nil
+}
+
+// detailRows returns how many content lines fit in the detail view
+func (m statsModel) detailRows() int {
+	return max(m.height-5, 1)
+}
+
+// renderDetail renders the full-screen detail view
+func (m statsModel) renderDetail() string {
+	d := m.detail
+
+	s := titleStyle.Render(fmt.Sprintf(" 🐳 %s ", d.name)) + dimStyle.Render(" "+d.id) + "\n"
+	for i, title := range detailTabs {
+		label := fmt.Sprintf(" %d %s ", i+1, title)
+		if detailTab(i) == d.tab {
+			s += selectedStyle.Render(label)
+		} else {
+			s += dimStyle.Render(label)
+		}
+		s += " "
+	}
+	s += "\n" + dimStyle.Render(repeatStr("─", m.width)) + "\n"
+
+	var lines []string
+	switch d.tab {
+	case tabMetrics:
+		lines = m.metricsLines()
+	case tabInspect:
+		lines = inspectLines(d, m.width)
+	case tabHealth:
+		lines = healthLines(d, m.width)
+	case tabProcesses:
+		lines = processLines(d, m.width)
+	}
+
+	rows := m.detailRows()
+	scroll := min(d.scroll, max(len(lines)-rows, 0))
+	end := min(scroll+rows, len(lines))
+	for _, line := range lines[scroll:end] {
+		s += line + "\n"
+	}
+	s += strings.Repeat("\n", rows-(end-scroll))
+
+	footer := cyanStyle.Render("[tab]") + " next " + cyanStyle.Render("[1-4]") + " tab " +
+		cyanStyle.Render("[↑↓]") + " scroll " + cyanStyle.Render("[r]") + "efresh " +
+		cyanStyle.Render("[esc]") + " back " + redStyle.Render("[q]") + "uit"
+	if len(lines) > rows {
+		footer += dimStyle.Render(fmt.Sprintf("  │  [%d-%d of %d]", scroll+1, end, len(lines)))
+	}
+	return s + footer
+}
+
+// metricsLines shows the live statistics of the container
+func (m statsModel) metricsLines() []string {
+	var c docker.ContainerStats
+	found := false
+	for _, cont := range m.all {
+		if cont.ID == m.detail.id {
+			c, found = cont, true
+			break
+		}
+	}
+	if !found {
+		return []string{dimStyle.Render("Container is no longer listed")}
+	}
+	if c.Unavailable() {
+		return []string{lifecycleLine(c), redStyle.Render("n/a: " + c.Error)}
+	}
+
+	label := func(s string) string { return dimStyle.Render(s) }
+	cpu := label("CPU      ") + fmt.Sprintf("%.1f%%", c.CPUPercent)
+	cpu += label("  host ") + fmt.Sprintf("%.1f%%", c.CPUPercentHost)
+	limit := "unlimited"
+	if c.CPULimit > 0 {
+		limit = fmt.Sprintf("%.2f CPUs", c.CPULimit)
+	}
+	cpu += label("  limit ") + limit
+	cpu += label("  throttled ") + throttleStyle(c.ThrottledPercent).Render(fmt.Sprintf("%.1f%%", c.ThrottledPercent))
+	cpu += label(fmt.Sprintf(" (%d periods, %s)", c.ThrottledPeriods, c.ThrottledTime.Round(time.Millisecond)))
+
+	lines := []string{lifecycleLine(c), cpu, cpuLine(c, m.width)}
+	if c.Stale {
+		lines = append([]string{yellowStyle.Render(c.Error)}, lines...)
+	}
+	lines = append(lines, memoryLines(c)...)
+	for _, iface := range c.Networks {
+		lines = append(lines, interfaceLine(iface))
+	}
+	for _, dev := range c.Devices {
+		lines = append(lines, deviceLine(dev))
+	}
+	return lines
+}
+
+// inspectLines shows the container configuration
+func inspectLines(d detailView, width int) []string {
+	if !d.loaded {
+		return []string{dimStyle.Render("Loading...")}
+	}
+	if d.err != nil {
+		return []string{redStyle.Render(d.err.Error())}
+	}
+	det := d.details
+
+	heading := func(s string) string { return yellowStyle.Render(s) }
+	field := func(name, value string) string {
+		return dimStyle.Render(fmt.Sprintf("  %-12s", name)) + truncate(value, max(width-14, 8))
+	}
+	item := func(value string) string { return "  " + truncate(value, max(width-2, 8)) }
+	orNone := func(lines []string) []string {
+		if len(lines) == 0 {
+			return []string{dimStyle.Render("  none")}
+		}
+		return lines
+	}
+
+	lines := []string{
+		heading("Container"),
+		field("Image", det.Image),
+		field("Command", strings.Join(det.Command, " ")),
+		field("WorkingDir", det.WorkingDir),
+		field("User", det.User),
+		"",
+		heading("Limits"),
+		field("CPUs", formatLimit(det.Limits.CPUs > 0, fmt.Sprintf("%.2f", det.Limits.CPUs))),
+		field("CPU shares", formatLimit(det.Limits.CPUShares > 0, fmt.Sprintf("%d", det.Limits.CPUShares))),
+		field("cpuset", formatLimit(det.Limits.CPUSet != "", det.Limits.CPUSet)),
+		field("Memory", formatLimit(det.Limits.Memory > 0, docker.FormatBytesInt64(det.Limits.Memory))),
+		field("Reservation", formatLimit(det.Limits.MemoryReservation > 0, docker.FormatBytesInt64(det.Limits.MemoryReservation))),
+		field("Swap", formatLimit(det.Limits.MemorySwap > 0, docker.FormatBytesInt64(det.Limits.MemorySwap))),
+		field("PIDs", formatLimit(det.Limits.PidsLimit > 0, fmt.Sprintf("%d", det.Limits.PidsLimit))),
+		"",
+		heading("Ports"),
+	}
+
+	var section []string
+	for _, p := range det.Ports {
+		if p.HostPort == "" {
+			section = append(section, item(p.ContainerPort+" (not published)"))
+		} else {
+			section = append(section, item(fmt.Sprintf("%s:%s → %s", p.HostIP, p.HostPort, p.ContainerPort)))
+		}
+	}
+	lines = append(lines, orNone(section)...)
+
+	lines = append(lines, "", heading("Networks"))
+	section = nil
+	for _, n := range det.Networks {
+		line := fmt.Sprintf("%-16s %-16s gw %-16s mac %s", n.Name, n.IPAddress, n.Gateway, n.MacAddress)
+		if len(n.Aliases) > 0 {
+			line += "  aliases " + strings.Join(n.Aliases, ",")
+		}
+		section = append(section, item(line))
+	}
+	lines = append(lines, orNone(section)...)
+
+	lines = append(lines, "", heading("Mounts"))
+	section = nil
+	for _, mnt := range det.Mounts {
+		mode := "ro"
+		if mnt.ReadWrite {
+			mode = "rw"
+		}
+		section = append(section, item(fmt.Sprintf("%-7s %s → %s (%s)", mnt.Type, mnt.Source, mnt.Destination, mode)))
+	}
+	lines = append(lines, orNone(section)...)
+
+	lines = append(lines, "", heading("Environment"))
+	section = nil
+	for _, env := range det.Env {
+		section = append(section, item(env))
+	}
+	lines = append(lines, orNone(section)...)
+
+	lines = append(lines, "", heading("Labels"))
+	section = nil
+	for _, key := range det.SortedLabels() {
+		section = append(section, item(key+"="+det.Labels[key]))
+	}
+	return append(lines, orNone(section)...)
+}
+
+// formatLimit returns value when a limit is set, or "unlimited"
+func formatLimit(set bool, value string) string {
+	if !set {
+		return "unlimited"
+	}
+	return value
+}
+
+// healthLines shows the health check command and its latest results
+func healthLines(d detailView, width int) []string {
+	if !d.loaded {
+		return []string{dimStyle.Render("Loading...")}
+	}
+	if d.err != nil {
+		return []string{redStyle.Render(d.err.Error())}
+	}
+	if len(d.details.Healthcheck) == 0 {
+		return []string{dimStyle.Render("No health check configured")}
+	}
+
+	lines := []string{dimStyle.Render("Test  ") + truncate(strings.Join(d.details.Healthcheck, " "), max(width-6, 8)), ""}
+	if len(d.details.HealthLog) == 0 {
+		return append(lines, dimStyle.Render("No results yet"))
+	}
+	// Newest first
+	for i := len(d.details.HealthLog) - 1; i >= 0; i-- {
+		r := d.details.HealthLog[i]
+		exitStyle := greenStyle
+		if r.ExitCode != 0 {
+			exitStyle = redStyle
+		}
+		lines = append(lines, r.Start.Local().Format("2006-01-02 15:04:05")+" "+
+			exitStyle.Render(fmt.Sprintf("exit %d", r.ExitCode))+
+			dimStyle.Render(fmt.Sprintf(" in %s", r.End.Sub(r.Start).Round(time.Millisecond))))
+		for _, out := range strings.Split(strings.TrimRight(r.Output, "\n"), "\n") {
+			if out != "" {
+				lines = append(lines, "  "+truncate(out, max(width-2, 8)))
+			}
+		}
+	}
+	return lines
+}
+
+// processLines shows the process table of the container
+func processLines(d detailView, width int) []string {
+	if !d.loaded {
+		return []string{dimStyle.Render("Loading...")}
+	}
+	if d.procErr != nil {
+		return []string{redStyle.Render(d.procErr.Error())}
+	}
+	if len(d.procs.Processes) == 0 {
+		return []string{dimStyle.Render("No processes")}
+	}
+
+	// Size every column but the last, which gets the remaining width
+	widths := make([]int, len(d.procs.Titles))
+	for i, title := range d.procs.Titles {
+		widths[i] = len(title)
+	}
+	for _, proc := range d.procs.Processes {
+		for i, value := range proc {
+			if i < len(widths) {
+				widths[i] = max(widths[i], len(value))
+			}
+		}
+	}
+
+	// Rows may have more values than titles
+	format := func(values []string) string {
+		var line string
+		for i, value := range values {
+			width := 0
+			if i < len(widths) {
+				width = widths[i]
+			}
+			if i == len(values)-1 {
+				line += value
+			} else {
+				line += fmt.Sprintf("%-*s ", width, value)
+			}
+		}
+		return truncate(line, max(width, 8))
+	}
+
+	lines := []string{headerStyle.Render(format(d.procs.Titles))}
+	for _, proc := range d.procs.Processes {
+		lines = append(lines, format(proc))
+	}
+	if !d.procs.HostStats {
+		lines = append(lines, "", dimStyle.Render(noHostStats))
+	}
+	return lines
+}
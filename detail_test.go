@@ -0,0 +1,22 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/tradik/cv-xslt/scripts/tools/stats/internal/docker"
+)
+
+func TestProcessLinesLongRow(t *testing.T) {
+	d := detailView{
+		loaded: true,
+		procs: docker.ProcessList{
+			Titles:    []string{"PID", "CMD"},
+			Processes: [][]string{{"1", "nginx"}, {"7", "sh", "-c", "sleep 1"}},
+			HostStats: true,
+		},
+	}
+	lines := processLines(d, 80)
+	if len(lines) != 3 || lines[2] != "7   sh    -c sleep 1" {
+		t.Errorf("processLines() with a row longer than the titles = %q", lines)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tradik/cv-xslt/scripts/tools/stats/internal/docker"
+)
+
+// updateFilterKeys handles keys while the filter prompt opened with / is
+// shown. The rows are narrowed as the query changes.
+func (m statsModel) updateFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.quitting = true
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.filtering, m.query = false, ""
+	case tea.KeyEnter:
+		m.filtering = false
+		return m, nil
+	case tea.KeyBackspace:
+		if r := []rune(m.query); len(r) > 0 {
+			m.query = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.query += msg.String()
+	default:
+		return m, nil
+	}
+	m.applyQuery()
+	return m, nil
+}
+
+// applyQuery narrows the rows to the containers matching the query. An
+// invalid query shows every container until it is fixed.
+func (m *statsModel) applyQuery() {
+	q, err := docker.ParseQuery(m.query)
+	m.queryErr = err
+	if err != nil {
+		m.containers = m.all
+	} else {
+		m.containers = docker.FilterContainers(m.all, q)
+	}
+	m.clampSelection()
+}
+
+// filterHeader renders the filter prompt or the active query with its
+// match count
+func (m statsModel) filterHeader() string {
+	if !m.filtering && m.query == "" {
+		return ""
+	}
+	s := dimStyle.Render(" │ ") + cyanStyle.Render("/") + m.query
+	if m.filtering {
+		s += "█"
+	}
+	if m.queryErr != nil {
+		return s + " " + redStyle.Render(m.queryErr.Error())
+	}
+	return s + " " + yellowStyle.Render(fmt.Sprintf("%d/%d", len(m.containers), len(m.all)))
+}
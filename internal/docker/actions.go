@@ -0,0 +1,160 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// Action is a lifecycle operation on a container
+type Action int
+
+const (
+	ActionStart Action = iota
+	ActionStop
+	ActionRestart
+	ActionPause
+	ActionUnpause
+	ActionKill
+)
+
+// String returns the name of the action
+func (a Action) String() string {
+	switch a {
+	case ActionStart:
+		return "start"
+	case ActionStop:
+		return "stop"
+	case ActionRestart:
+		return "restart"
+	case ActionPause:
+		return "pause"
+	case ActionUnpause:
+		return "unpause"
+	case ActionKill:
+		return "kill"
+	}
+	return fmt.Sprintf("action(%d)", int(a))
+}
+
+// KillSignals lists the signals offered for killing a container, the
+// default first
+var KillSignals = []string{"SIGKILL", "SIGTERM", "SIGINT", "SIGHUP", "SIGQUIT", "SIGUSR1", "SIGUSR2"}
+
+// StartStopAction returns the action that toggles a container in the given
+// state between running and stopped
+func StartStopAction(state string) Action {
+	switch state {
+	case "running", "paused", "restarting":
+		return ActionStop
+	}
+	return ActionStart
+}
+
+// PauseAction returns the action that toggles a container in the given
+// state between running and paused
+func PauseAction(state string) Action {
+	if state == "paused" {
+		return ActionUnpause
+	}
+	return ActionPause
+}
+
+// SetReadOnly selects whether container actions are refused with
+// ErrReadOnly
+func (c *Client) SetReadOnly(readOnly bool) {
+	c.readOnly.Store(readOnly)
+}
+
+// ReadOnly reports whether container actions are disabled
+func (c *Client) ReadOnly() bool {
+	return c.readOnly.Load()
+}
+
+// StartContainer starts a stopped container
+func (c *Client) StartContainer(ctx context.Context, id string) error {
+	if c.ReadOnly() {
+		return ErrReadOnly
+	}
+	if err := c.cli.ContainerStart(ctx, id, container.StartOptions{}); err != nil {
+		return fmt.Errorf("failed to start container: %w", err)
+	}
+	return nil
+}
+
+// StopContainer stops a container, killing it after the daemon's grace
+// period
+func (c *Client) StopContainer(ctx context.Context, id string) error {
+	if c.ReadOnly() {
+		return ErrReadOnly
+	}
+	if err := c.cli.ContainerStop(ctx, id, container.StopOptions{}); err != nil {
+		return fmt.Errorf("failed to stop container: %w", err)
+	}
+	return nil
+}
+
+// RestartContainer stops and starts a container
+func (c *Client) RestartContainer(ctx context.Context, id string) error {
+	if c.ReadOnly() {
+		return ErrReadOnly
+	}
+	if err := c.cli.ContainerRestart(ctx, id, container.StopOptions{}); err != nil {
+		return fmt.Errorf("failed to restart container: %w", err)
+	}
+	return nil
+}
+
+// PauseContainer freezes all processes of a container
+func (c *Client) PauseContainer(ctx context.Context, id string) error {
+	if c.ReadOnly() {
+		return ErrReadOnly
+	}
+	if err := c.cli.ContainerPause(ctx, id); err != nil {
+		return fmt.Errorf("failed to pause container: %w", err)
+	}
+	return nil
+}
+
+// UnpauseContainer resumes the processes of a paused container
+func (c *Client) UnpauseContainer(ctx context.Context, id string) error {
+	if c.ReadOnly() {
+		return ErrReadOnly
+	}
+	if err := c.cli.ContainerUnpause(ctx, id); err != nil {
+		return fmt.Errorf("failed to unpause container: %w", err)
+	}
+	return nil
+}
+
+// KillContainer sends a signal to the main process of a container
+func (c *Client) KillContainer(ctx context.Context, id, signal string) error {
+	if c.ReadOnly() {
+		return ErrReadOnly
+	}
+	if err := c.cli.ContainerKill(ctx, id, signal); err != nil {
+		return fmt.Errorf("failed to kill container: %w", err)
+	}
+	return nil
+}
+
+// RunAction performs an action on a container. The signal is only used by
+// ActionKill.
+func (c *Client) RunAction(ctx context.Context, action Action, id, signal string) error {
+	switch action {
+	case ActionStart:
+		return c.StartContainer(ctx, id)
+	case ActionStop:
+		return c.StopContainer(ctx, id)
+	case ActionRestart:
+		return c.RestartContainer(ctx, id)
+	case ActionPause:
+		return c.PauseContainer(ctx, id)
+	case ActionUnpause:
+		return c.UnpauseContainer(ctx, id)
+	case ActionKill:
+		return c.KillContainer(ctx, id, signal)
+	}
+	return fmt.Errorf("unknown action %v", action)
+}
@@ -0,0 +1,68 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRunAction(t *testing.T) {
+	api := newFakeAPI()
+	c := newClient(api)
+	defer c.Close()
+
+	ctx := context.Background()
+	for _, action := range []Action{ActionStart, ActionStop, ActionRestart, ActionPause, ActionUnpause, ActionKill} {
+		if err := c.RunAction(ctx, action, "web", "SIGHUP"); err != nil {
+			t.Fatalf("RunAction(%v) error = %v", action, err)
+		}
+	}
+	want := []string{"start web", "stop web", "restart web", "pause web", "unpause web", "kill web SIGHUP"}
+	if !reflect.DeepEqual(api.actions, want) {
+		t.Errorf("daemon calls = %q; want %q", api.actions, want)
+	}
+
+	err := c.StopContainer(ctx, "missing")
+	if err == nil || err.Error() != "failed to stop container: no such container: missing" {
+		t.Errorf("StopContainer(missing) error = %v; want wrapped daemon error", err)
+	}
+}
+
+func TestRunActionReadOnly(t *testing.T) {
+	api := newFakeAPI()
+	c := newClient(api)
+	defer c.Close()
+	c.SetReadOnly(true)
+
+	for _, action := range []Action{ActionStart, ActionStop, ActionRestart, ActionPause, ActionUnpause, ActionKill} {
+		if err := c.RunAction(context.Background(), action, "web", "SIGKILL"); !errors.Is(err, ErrReadOnly) {
+			t.Errorf("RunAction(%v) error = %v; want ErrReadOnly", action, err)
+		}
+	}
+	if len(api.actions) != 0 {
+		t.Errorf("daemon calls = %q; want none in read-only mode", api.actions)
+	}
+}
+
+func TestToggleActions(t *testing.T) {
+	tests := []struct {
+		state     string
+		startStop Action
+		pause     Action
+	}{
+		{"running", ActionStop, ActionPause},
+		{"paused", ActionStop, ActionUnpause},
+		{"restarting", ActionStop, ActionPause},
+		{"exited", ActionStart, ActionPause},
+		{"created", ActionStart, ActionPause},
+	}
+	for _, tt := range tests {
+		if got := StartStopAction(tt.state); got != tt.startStop {
+			t.Errorf("StartStopAction(%q) = %v; want %v", tt.state, got, tt.startStop)
+		}
+		if got := PauseAction(tt.state); got != tt.pause {
+			t.Errorf("PauseAction(%q) = %v; want %v", tt.state, got, tt.pause)
+		}
+	}
+}
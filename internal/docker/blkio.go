@@ -0,0 +1,186 @@
+package docker
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DeviceStats holds the I/O counters of one block device used by a
+// container
+type DeviceStats struct {
+	Major      uint64  `json:"major"`
+	Minor      uint64  `json:"minor"`
+	Name       string  `json:"name"` // Device name, e.g. sda; major:minor when unknown
+	ReadBytes  uint64  `json:"read_bytes"`
+	WriteBytes uint64  `json:"write_bytes"`
+	ReadOps    uint64  `json:"read_ops"`
+	WriteOps   uint64  `json:"write_ops"`
+	ReadRate   float64 `json:"read_rate"`  // Bytes per second read
+	WriteRate  float64 `json:"write_rate"` // Bytes per second written
+	ReadIOPS   float64 `json:"read_iops"`  // Read operations per second
+	WriteIOPS  float64 `json:"write_iops"` // Write operations per second
+}
+
+// deviceCounters holds the cumulative counters of one device
+type deviceCounters struct {
+	major, minor                             uint64
+	readBytes, writeBytes, readOps, writeOps uint64
+}
+
+// deviceRates holds the per-second throughput of one device
+type deviceRates struct {
+	Read      float64
+	Write     float64
+	ReadIOPS  float64
+	WriteIOPS float64
+}
+
+// deviceKey identifies a block device as major:minor
+func deviceKey(major, minor uint64) string {
+	return fmt.Sprintf("%d:%d", major, minor)
+}
+
+// blkioOp classifies an entry's operation. cgroup v1 reports capitalised
+// operations plus aggregates such as Total and Sync, while cgroup v2
+// reports lowercase read and write only. Only reads and writes are
+// counted so aggregates are not added twice.
+func blkioOp(op string) (read, write bool) {
+	return strings.EqualFold(op, "read"), strings.EqualFold(op, "write")
+}
+
+// deviceTotals groups the byte and operation counters by device
+func deviceTotals(stats *StatsJSON) map[string]*deviceCounters {
+	devices := make(map[string]*deviceCounters)
+	device := func(e BlkioStatEntry) *deviceCounters {
+		key := deviceKey(e.Major, e.Minor)
+		d, ok := devices[key]
+		if !ok {
+			d = &deviceCounters{major: e.Major, minor: e.Minor}
+			devices[key] = d
+		}
+		return d
+	}
+
+	for _, e := range stats.BlkioStats.IoServiceBytesRecursive {
+		switch read, write := blkioOp(e.Op); {
+		case read:
+			device(e).readBytes += e.Value
+		case write:
+			device(e).writeBytes += e.Value
+		}
+	}
+	for _, e := range stats.BlkioStats.IoServicedRecursive {
+		switch read, write := blkioOp(e.Op); {
+		case read:
+			device(e).readOps += e.Value
+		case write:
+			device(e).writeOps += e.Value
+		}
+	}
+	return devices
+}
+
+// blockOps sums the read and write operations over all block devices
+func blockOps(stats *StatsJSON) (read, write uint64) {
+	for _, e := range stats.BlkioStats.IoServicedRecursive {
+		switch r, w := blkioOp(e.Op); {
+		case r:
+			read += e.Value
+		case w:
+			write += e.Value
+		}
+	}
+	return read, write
+}
+
+// deviceStats returns the counters of each device, sorted by major:minor
+func deviceStats(stats *StatsJSON, rates map[string]deviceRates, names *deviceNames) []DeviceStats {
+	totals := deviceTotals(stats)
+	if len(totals) == 0 {
+		return nil
+	}
+	devices := make([]DeviceStats, 0, len(totals))
+	for key, d := range totals {
+		r := rates[key]
+		devices = append(devices, DeviceStats{
+			Major:      d.major,
+			Minor:      d.minor,
+			Name:       names.lookup(d.major, d.minor),
+			ReadBytes:  d.readBytes,
+			WriteBytes: d.writeBytes,
+			ReadOps:    d.readOps,
+			WriteOps:   d.writeOps,
+			ReadRate:   r.Read,
+			WriteRate:  r.Write,
+			ReadIOPS:   r.ReadIOPS,
+			WriteIOPS:  r.WriteIOPS,
+		})
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		if devices[i].Major != devices[j].Major {
+			return devices[i].Major < devices[j].Major
+		}
+		return devices[i].Minor < devices[j].Minor
+	})
+	return devices
+}
+
+// deviceNames resolves major:minor numbers to device names and caches the
+// result, including failures, since devices rarely change
+type deviceNames struct {
+	read func(major, minor uint64) (string, error)
+
+	mu    sync.Mutex
+	names map[string]string
+}
+
+// newDeviceNames creates a resolver reading from /sys/dev/block
+func newDeviceNames() *deviceNames {
+	return &deviceNames{read: sysfsDeviceName, names: make(map[string]string)}
+}
+
+// lookup returns the name of a device, or major:minor when it is unknown
+func (dn *deviceNames) lookup(major, minor uint64) string {
+	key := deviceKey(major, minor)
+	if dn == nil || dn.read == nil {
+		return key
+	}
+
+	dn.mu.Lock()
+	defer dn.mu.Unlock()
+	if name, ok := dn.names[key]; ok {
+		return name
+	}
+	name, err := dn.read(major, minor)
+	if err != nil || name == "" {
+		name = key
+	}
+	dn.names[key] = name
+	return name
+}
+
+// sysfsDeviceName reads a device name from /sys/dev/block/<major>:<minor>/uevent,
+// which is only available when running on the Docker host
+func sysfsDeviceName(major, minor uint64) (string, error) {
+	path := fmt.Sprintf("/sys/dev/block/%d:%d/uevent", major, minor)
+	f, err := os.Open(path) // #nosec G304 - path is built from device numbers
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close() //nolint:errcheck // read-only file
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if name, ok := strings.CutPrefix(scanner.Text(), "DEVNAME="); ok {
+			return name, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return "", fmt.Errorf("no DEVNAME in %s", path)
+}
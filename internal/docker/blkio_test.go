@@ -0,0 +1,87 @@
+package docker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeviceStatsFixtures(t *testing.T) {
+	tests := []struct {
+		fixture string
+		devices []DeviceStats
+		read    uint64
+		write   uint64
+	}{
+		{"stats_cgroup_v1.json", []DeviceStats{
+			{Major: 8, Minor: 0, ReadBytes: 10485760, WriteBytes: 4194304, ReadOps: 250, WriteOps: 100},
+			{Major: 253, Minor: 1, ReadBytes: 1048576, ReadOps: 16},
+		}, 11534336, 4194304},
+		{"stats_cgroup_v2.json", []DeviceStats{
+			{Major: 259, Minor: 0, ReadBytes: 20971520, WriteBytes: 8388608, ReadOps: 400, WriteOps: 150},
+		}, 20971520, 8388608},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			stats := fixtureStats(t, tt.fixture, func(c *Client) { c.devices = &deviceNames{names: map[string]string{}} })
+			if stats.BlockRead != tt.read || stats.BlockWrite != tt.write {
+				t.Errorf("BlockRead, BlockWrite = %d, %d; want %d, %d", stats.BlockRead, stats.BlockWrite, tt.read, tt.write)
+			}
+			if len(stats.Devices) != len(tt.devices) {
+				t.Fatalf("Devices = %+v; want %d devices", stats.Devices, len(tt.devices))
+			}
+			for i, want := range tt.devices {
+				want.Name = deviceKey(want.Major, want.Minor)
+				if stats.Devices[i] != want {
+					t.Errorf("Devices[%d] = %+v; want %+v", i, stats.Devices[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestBlockRatesIOPS(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sample := func(offset time.Duration, readOps, writeOps uint64) *StatsJSON {
+		return &StatsJSON{
+			Read: at.Add(offset),
+			BlkioStats: BlkioStats{IoServicedRecursive: []BlkioStatEntry{
+				{Major: 8, Op: "Read", Value: readOps},
+				{Major: 8, Op: "Write", Value: writeOps},
+				{Major: 8, Op: "Total", Value: readOps + writeOps},
+			}},
+		}
+	}
+
+	rates := calculateRates(sample(0, 100, 50), sample(2*time.Second, 300, 70))
+	if rates.ReadIOPS != 100 || rates.WriteIOPS != 10 {
+		t.Errorf("ReadIOPS, WriteIOPS = %f, %f; want 100, 10", rates.ReadIOPS, rates.WriteIOPS)
+	}
+	if d := rates.Devices["8:0"]; d.ReadIOPS != 100 || d.WriteIOPS != 10 {
+		t.Errorf("Devices[8:0] = %+v; want 100 read and 10 write IOPS", d)
+	}
+}
+
+func TestDeviceNamesLookup(t *testing.T) {
+	reads := 0
+	dn := &deviceNames{names: map[string]string{}, read: func(major, minor uint64) (string, error) {
+		reads++
+		if major == 8 {
+			return "sda", nil
+		}
+		return "", errors.New("unknown device")
+	}}
+
+	if name := dn.lookup(8, 0); name != "sda" {
+		t.Errorf("lookup(8, 0) = %q; want sda", name)
+	}
+	if name := dn.lookup(253, 1); name != "253:1" {
+		t.Errorf("lookup(253, 1) = %q; want 253:1", name)
+	}
+	dn.lookup(8, 0)
+	dn.lookup(253, 1)
+	if reads != 2 {
+		t.Errorf("device names read %d times; want 2", reads)
+	}
+}
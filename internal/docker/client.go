@@ -4,11 +4,12 @@ package docker
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"math"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/docker/docker/api/types/container"
@@ -18,6 +19,7 @@ import (
 
 // StatsJSON is the stats response from Docker API
 type StatsJSON struct {
+	Read        time.Time           `json:"read"`
 	CPUStats    CPUStats            `json:"cpu_stats"`
 	PreCPUStats CPUStats            `json:"precpu_stats"`
 	MemoryStats MemoryStats         `json:"memory_stats"`
@@ -28,9 +30,17 @@ type StatsJSON struct {
 
 // CPUStats represents CPU statistics
 type CPUStats struct {
-	CPUUsage    CPUUsage `json:"cpu_usage"`
-	SystemUsage uint64   `json:"system_cpu_usage"`
-	OnlineCPUs  uint32   `json:"online_cpus"`
+	CPUUsage       CPUUsage       `json:"cpu_usage"`
+	SystemUsage    uint64         `json:"system_cpu_usage"`
+	OnlineCPUs     uint32         `json:"online_cpus"`
+	ThrottlingData ThrottlingData `json:"throttling_data"`
+}
+
+// ThrottlingData represents CFS bandwidth throttling counters
+type ThrottlingData struct {
+	Periods          uint64 `json:"periods"`           // Enforcement intervals that elapsed
+	ThrottledPeriods uint64 `json:"throttled_periods"` // Intervals in which the container was throttled
+	ThrottledTime    uint64 `json:"throttled_time"`    // Total time throttled in nanoseconds
 }
 
 // CPUUsage represents CPU usage details
@@ -39,25 +49,38 @@ type CPUUsage struct {
 	PercpuUsage []uint64 `json:"percpu_usage"`
 }
 
-// MemoryStats represents memory statistics
+// MemoryStats represents memory statistics. Stats holds the raw
+// memory.stat counters, whose keys differ between cgroup v1 and v2.
 type MemoryStats struct {
-	Usage uint64 `json:"usage"`
-	Limit uint64 `json:"limit"`
+	Usage    uint64            `json:"usage"`
+	MaxUsage uint64            `json:"max_usage"` // cgroup v1 only
+	Failcnt  uint64            `json:"failcnt"`   // cgroup v1 only
+	Limit    uint64            `json:"limit"`
+	Stats    map[string]uint64 `json:"stats"`
 }
 
 // NetStats represents network statistics
 type NetStats struct {
-	RxBytes uint64 `json:"rx_bytes"`
-	TxBytes uint64 `json:"tx_bytes"`
+	RxBytes   uint64 `json:"rx_bytes"`
+	RxPackets uint64 `json:"rx_packets"`
+	RxErrors  uint64 `json:"rx_errors"`
+	RxDropped uint64 `json:"rx_dropped"`
+	TxBytes   uint64 `json:"tx_bytes"`
+	TxPackets uint64 `json:"tx_packets"`
+	TxErrors  uint64 `json:"tx_errors"`
+	TxDropped uint64 `json:"tx_dropped"`
 }
 
 // BlkioStats represents block I/O statistics
 type BlkioStats struct {
 	IoServiceBytesRecursive []BlkioStatEntry `json:"io_service_bytes_recursive"`
+	IoServicedRecursive     []BlkioStatEntry `json:"io_serviced_recursive"`
 }
 
 // BlkioStatEntry represents a single block I/O stat entry
 type BlkioStatEntry struct {
+	Major uint64 `json:"major"`
+	Minor uint64 `json:"minor"`
 	Op    string `json:"op"`
 	Value uint64 `json:"value"`
 }
@@ -65,33 +88,138 @@ type BlkioStatEntry struct {
 // PidsStats represents process statistics
 type PidsStats struct {
 	Current uint64 `json:"current"`
+	Limit   uint64 `json:"limit"`
 }
 
+// firstSampleTimeout bounds how long GetContainerStats waits for newly
+// subscribed containers to deliver their first stats sample
+const firstSampleTimeout = 3 * time.Second
+
 // Client wraps the Docker client with additional functionality
 type Client struct {
-	cli *client.Client
+	cli       client.APIClient
+	registry  *containerRegistry
+	collector *statsCollector
+	inspect   *inspectCache
+
+	rawMemory atomic.Bool
+	cpuMode   atomic.Int32
+	readOnly  atomic.Bool
+	macs      *macCache
+	devices   *deviceNames
+	procs     *procSampler
+
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 // ContainerStats holds statistics for a single container
 type ContainerStats struct {
-	ID            string
-	Name          string
-	Image         string
-	Status        string
-	State         string
-	CPUPercent    float64
-	CPULimit      float64 // Number of CPUs (e.g., 2.0 = 2 CPUs, 0.5 = half CPU)
-	MemUsage      uint64
-	MemLimit      uint64
-	MemPercent    float64
-	NetRx         uint64
-	NetTx         uint64
-	BlockRead     uint64
-	BlockWrite    uint64
-	PIDs          uint64
-	ImageSize     int64
-	ContainerSize int64
-	Created       time.Time
+	ID               string            `json:"id"`
+	Name             string            `json:"name"`
+	Image            string            `json:"image"`
+	Status           string            `json:"status"`
+	State            string            `json:"state"`
+	Labels           map[string]string `json:"labels,omitempty"`
+	CPUPercent       float64           `json:"cpu_percent"`               // Relative to one host core, or to the CPU limit with CPUModeLimit
+	CPUPercentHost   float64           `json:"cpu_percent_host"`          // Relative to one host core, like 'docker stats'
+	CPULimit         float64           `json:"cpu_limit"`                 // Number of CPUs (e.g., 2.0 = 2 CPUs, 0.5 = half CPU)
+	OnlineCPUs       uint32            `json:"online_cpus"`               // Host CPUs available to the container
+	PerCPUPercent    []float64         `json:"per_cpu_percent,omitempty"` // Usage of each host core, relative to one core; cgroup v1 only
+	CPUSet           string            `json:"cpuset,omitempty"`          // CPUs the container may run on, e.g. "0-3"; empty when unrestricted
+	CPUTime          time.Duration     `json:"cpu_time_ns"`               // CPU time used since container start
+	ThrottledPercent float64           `json:"throttled_percent"`         // Share of CFS periods throttled since the previous sample
+	ThrottledPeriods uint64            `json:"throttled_periods"`         // Throttled periods since container start
+	ThrottledTime    time.Duration     `json:"throttled_time_ns"`         // Time throttled since container start
+	MemUsage         uint64            `json:"mem_usage"`                 // Usage as shown by 'docker stats', or raw usage with SetRawMemory
+	MemUsageRaw      uint64            `json:"mem_usage_raw"`             // Usage including the page cache
+	MemLimit         uint64            `json:"mem_limit"`
+	MemPercent       float64           `json:"mem_percent"`
+	MemAnon          uint64            `json:"mem_anon"`      // Anonymous memory (RSS)
+	MemFile          uint64            `json:"mem_file"`      // File-backed page cache
+	MemShmem         uint64            `json:"mem_shmem"`     // Shared memory and tmpfs
+	MemSwap          uint64            `json:"mem_swap"`      // Swap usage (cgroup v1 only)
+	MemMaxUsage      uint64            `json:"mem_max_usage"` // Peak usage (cgroup v1 only)
+	MemFailcnt       uint64            `json:"mem_failcnt"`   // Times usage hit the limit (cgroup v1 only)
+	OOMKills         uint64            `json:"oom_kills"`     // OOM events seen since monitoring started
+	NetRx            uint64            `json:"net_rx"`
+	NetTx            uint64            `json:"net_tx"`
+	BlockRead        uint64            `json:"block_read"`
+	BlockWrite       uint64            `json:"block_write"`
+	NetRxRate        float64           `json:"net_rx_rate"`        // Bytes per second received
+	NetTxRate        float64           `json:"net_tx_rate"`        // Bytes per second sent
+	BlockReadRate    float64           `json:"block_read_rate"`    // Bytes per second read from disk
+	BlockWriteRate   float64           `json:"block_write_rate"`   // Bytes per second written to disk
+	BlockReadIOPS    float64           `json:"block_read_iops"`    // Read operations per second
+	BlockWriteIOPS   float64           `json:"block_write_iops"`   // Write operations per second
+	Networks         []InterfaceStats  `json:"networks,omitempty"` // Per-interface traffic, sorted by interface name
+	Devices          []DeviceStats     `json:"devices,omitempty"`  // Per-device block I/O, sorted by major:minor
+	PIDs             uint64            `json:"pids"`
+	PIDsLimit        uint64            `json:"pids_limit"` // 0 when unlimited
+	ImageSize        int64             `json:"image_size"`
+	ContainerSize    int64             `json:"container_size"`
+	Created          time.Time         `json:"created"`
+
+	// Lifecycle from container inspect
+	Health              string    `json:"health,omitempty"`      // healthy, unhealthy or starting; empty without a health check
+	HealthFailingStreak int       `json:"health_failing_streak"` // Consecutive failed health checks
+	RestartCount        int       `json:"restart_count"`
+	RestartPolicy       string    `json:"restart_policy,omitempty"` // e.g. no, always, unless-stopped, on-failure:3
+	OOMKilled           bool      `json:"oom_killed"`               // The last exit was caused by the OOM killer
+	ExitCode            int       `json:"exit_code"`
+	StartedAt           time.Time `json:"started_at,omitzero"`
+	FinishedAt          time.Time `json:"finished_at,omitzero"`
+
+	// Collection status
+	Error     string    `json:"error,omitempty"`     // Why statistics are missing or stale; empty when current
+	Stale     bool      `json:"stale"`               // Statistics are from an old sample
+	SampledAt time.Time `json:"sampled_at,omitzero"` // When the latest stats sample was received
+}
+
+// Uptime returns how long a running container has been up, or zero
+func (s ContainerStats) Uptime() time.Duration {
+	if s.State != "running" || s.StartedAt.IsZero() {
+		return 0
+	}
+	return time.Since(s.StartedAt)
+}
+
+// Unavailable reports whether the statistics of a running container could
+// not be collected, so its usage values must not be read as zero usage.
+// Stale statistics still hold the last sample and are available.
+func (s ContainerStats) Unavailable() bool {
+	return s.Error != "" && !s.Stale
+}
+
+// CPUMode selects what CPUPercent is relative to
+type CPUMode int32
+
+const (
+	// CPUModeHost reports CPU usage relative to one host core, so a
+	// container using two full cores shows 200%
+	CPUModeHost CPUMode = iota
+	// CPUModeLimit reports CPU usage relative to the container's CPU limit,
+	// or to all host cores when the container is unlimited
+	CPUModeLimit
+)
+
+// String returns the name of the mode
+func (m CPUMode) String() string {
+	if m == CPUModeLimit {
+		return "limit"
+	}
+	return "host"
+}
+
+// ParseCPUMode parses a mode name as returned by CPUMode.String
+func ParseCPUMode(name string) (CPUMode, error) {
+	switch name {
+	case "host":
+		return CPUModeHost, nil
+	case "limit":
+		return CPUModeLimit, nil
+	}
+	return CPUModeHost, fmt.Errorf("unknown CPU mode %q (want host or limit)", name)
 }
 
 // SortField represents the field to sort containers by
@@ -104,6 +232,9 @@ const (
 	SortByNetIO
 	SortByBlockIO
 	SortByImageSize
+	SortByNetRate
+	SortByBlockRate
+	SortByThrottle
 )
 
 // NewClient creates a new Docker client
@@ -124,23 +255,86 @@ func NewClient() (*Client, error) {
 		return nil, fmt.Errorf("failed to connect to Docker daemon: %w", err)
 	}
 
-	return &Client{cli: cli}, nil
+	return newClient(cli), nil
+}
+
+// newClient wraps an API client and starts the background machinery
+func newClient(cli client.APIClient) *Client {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &Client{
+		cli:       cli,
+		registry:  newContainerRegistry(cli),
+		collector: newStatsCollector(ctx, cli),
+		inspect:   newInspectCache(cli, inspectTTL),
+		macs:      newMACCache(procMAC),
+		devices:   newDeviceNames(),
+		procs:     newProcSampler(),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	c.registry.invalidate = c.inspect.invalidate
+	c.registry.restarted = c.macs.invalidate
+	return c
 }
 
-// Close closes the Docker client connection
+// Close stops all stats streams and closes the Docker client connection
 func (c *Client) Close() error {
+	c.cancel()
 	return c.cli.Close()
 }
 
+// Changes returns a channel that receives a value whenever the container
+// set changes, e.g. a container was created, started or died. Signals are
+// coalesced, so a receiver may see one value for several changes. The
+// channel is shared, so only one goroutine should receive from it.
+func (c *Client) Changes() <-chan struct{} {
+	return c.registry.changes
+}
+
+// SetRawMemory selects whether memory usage includes the page cache. By
+// default usage is computed like the Docker CLI does, without the
+// reclaimable inactive file cache.
+func (c *Client) SetRawMemory(raw bool) {
+	c.rawMemory.Store(raw)
+}
+
+// SetCPUMode selects what CPU percentages are relative to
+func (c *Client) SetCPUMode(mode CPUMode) {
+	c.cpuMode.Store(int32(mode))
+}
+
+// CPUMode returns the current CPU percentage mode
+func (c *Client) CPUMode() CPUMode {
+	return CPUMode(c.cpuMode.Load())
+}
+
+// InspectCacheStats returns the hit and miss counters of the inspect cache
+func (c *Client) InspectCacheStats() CacheStats {
+	return c.inspect.stats()
+}
+
 // GetContainerStats retrieves statistics for all containers
 func (c *Client) GetContainerStats(ctx context.Context, showAll bool) ([]ContainerStats, error) {
-	// List containers
-	containers, err := c.cli.ContainerList(ctx, container.ListOptions{
-		All:  showAll,
-		Size: true,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list containers: %w", err)
+	// Containers come from the event-driven registry; only the first
+	// call lists them from the daemon
+	if err := c.registry.ensure(ctx, c.ctx); err != nil {
+		return nil, err
+	}
+	containers := c.registry.list(showAll)
+
+	// Keep one stats stream per running container and give newly
+	// subscribed ones a moment to deliver their first sample
+	running := make([]string, 0, len(containers))
+	for _, cont := range containers {
+		if cont.State == "running" {
+			running = append(running, cont.ID)
+		}
+	}
+	started := c.collector.sync(running)
+	if len(started) > 0 {
+		waitCtx, cancel := context.WithTimeout(ctx, firstSampleTimeout)
+		c.collector.wait(waitCtx, started)
+		cancel()
 	}
 
 	if len(containers) == 0 {
@@ -150,7 +344,7 @@ func (c *Client) GetContainerStats(ctx context.Context, showAll bool) ([]Contain
 	// Get stats for each container concurrently
 	var wg sync.WaitGroup
 	statsChan := make(chan ContainerStats, len(containers))
-	errChan := make(chan error, len(containers))
+	errChan := make(chan ContainerError, len(containers))
 
 	for _, cont := range containers {
 		wg.Add(1)
@@ -159,8 +353,7 @@ func (c *Client) GetContainerStats(ctx context.Context, showAll bool) ([]Contain
 
 			stats, err := c.getContainerStats(ctx, cont)
 			if err != nil {
-				errChan <- err
-				return
+				errChan <- ContainerError{ID: stats.ID, Name: stats.Name, Err: err}
 			}
 			statsChan <- stats
 		}(cont)
@@ -176,10 +369,21 @@ func (c *Client) GetContainerStats(ctx context.Context, showAll bool) ([]Contain
 		result = append(result, stats)
 	}
 
+	var failures []ContainerError
+	for err := range errChan {
+		failures = append(failures, err)
+	}
+	if len(failures) > 0 {
+		sort.Slice(failures, func(i, j int) bool { return failures[i].Name < failures[j].Name })
+		return result, &PartialError{Total: len(result), Failures: failures}
+	}
+
 	return result, nil
 }
 
-// getContainerStats retrieves statistics for a single container
+// getContainerStats retrieves statistics for a single container. The
+// returned error explains why live statistics are missing or stale; the
+// stats are usable either way.
 func (c *Client) getContainerStats(ctx context.Context, cont container.Summary) (ContainerStats, error) {
 	stats := ContainerStats{
 		ID:      cont.ID[:12],
@@ -187,6 +391,7 @@ func (c *Client) getContainerStats(ctx context.Context, cont container.Summary)
 		Image:   cont.Image,
 		Status:  cont.Status,
 		State:   cont.State,
+		Labels:  cont.Labels,
 		Created: time.Unix(cont.Created, 0),
 	}
 
@@ -194,14 +399,14 @@ func (c *Client) getContainerStats(ctx context.Context, cont container.Summary)
 	stats.ContainerSize = cont.SizeRw
 
 	// Get image size
-	imageInfo, err := c.cli.ImageInspect(ctx, cont.ImageID)
+	imageInfo, err := c.inspect.image(ctx, cont.ImageID)
 	if err == nil {
 		stats.ImageSize = imageInfo.Size
 	}
 
 	// Get CPU limit from container inspect
-	containerInfo, err := c.cli.ContainerInspect(ctx, cont.ID)
-	if err == nil {
+	containerInfo, inspectErr := c.inspect.container(ctx, cont.ID)
+	if inspectErr == nil && containerInfo.ContainerJSONBase != nil && containerInfo.HostConfig != nil {
 		// NanoCPUs is in units of 10^-9 CPUs
 		if containerInfo.HostConfig.NanoCPUs > 0 {
 			stats.CPULimit = float64(containerInfo.HostConfig.NanoCPUs) / 1e9
@@ -210,76 +415,268 @@ func (c *Client) getContainerStats(ctx context.Context, cont container.Summary)
 			stats.CPULimit = float64(containerInfo.HostConfig.CPUQuota) / float64(containerInfo.HostConfig.CPUPeriod)
 		}
 		// 0 means unlimited
+		stats.CPUSet = containerInfo.HostConfig.CpusetCpus
+		stats.RestartPolicy = restartPolicy(containerInfo.HostConfig.RestartPolicy)
+	}
+	if inspectErr == nil && containerInfo.ContainerJSONBase != nil {
+		stats.RestartCount = containerInfo.RestartCount
+		applyState(&stats, containerInfo.State)
 	}
 
+	stats.OOMKills = c.registry.oomKills(cont.ID)
+
 	// Skip stats for non-running containers
 	if cont.State != "running" {
 		return stats, nil
 	}
 
-	// Get live stats
-	statsResp, err := c.cli.ContainerStats(ctx, cont.ID, false)
-	if err != nil {
-		return stats, nil // Return partial stats on error
+	// Latest sample from the container's stats stream
+	latest, sampleErr := c.collector.latest(cont.ID)
+	if sampleErr != nil {
+		stats.Error = sampleErr.Error()
+		stats.Stale = errors.Is(sampleErr, ErrStaleStats)
 	}
-	defer statsResp.Body.Close() //nolint:errcheck // intentionally ignoring close error
-
-	var statsJSON StatsJSON
-	decoder := json.NewDecoder(statsResp.Body)
-	if err := decoder.Decode(&statsJSON); err != nil && err != io.EOF {
-		return stats, nil
+	statsJSON := latest.stats
+	if statsJSON == nil {
+		return stats, sampleErr // Return partial stats without usage values
 	}
+	stats.SampledAt = latest.received
 
 	// Calculate CPU percentage
-	stats.CPUPercent = calculateCPUPercent(&statsJSON)
+	stats.CPUPercentHost = calculateCPUPercent(statsJSON)
+	stats.OnlineCPUs = onlineCPUs(statsJSON)
+	stats.PerCPUPercent = calculatePerCPUPercent(statsJSON)
+	stats.CPUPercent = stats.CPUPercentHost
+	if c.CPUMode() == CPUModeLimit {
+		stats.CPUPercent = normalizeCPUPercent(stats.CPUPercentHost, stats.CPULimit, stats.OnlineCPUs)
+	}
+
+	stats.CPUTime = time.Duration(statsJSON.CPUStats.CPUUsage.TotalUsage) // #nosec G115 - nanoseconds fit in int64 for centuries
+
+	// CPU throttling
+	stats.ThrottledPercent = calculateThrottledPercent(statsJSON)
+	stats.ThrottledPeriods = statsJSON.CPUStats.ThrottlingData.ThrottledPeriods
+	stats.ThrottledTime = time.Duration(statsJSON.CPUStats.ThrottlingData.ThrottledTime) // #nosec G115 - nanoseconds fit in int64 for centuries
 
 	// Memory stats
-	stats.MemUsage = statsJSON.MemoryStats.Usage
+	stats.MemUsageRaw = statsJSON.MemoryStats.Usage
+	stats.MemUsage = calculateMemUsage(statsJSON.MemoryStats)
+	if c.rawMemory.Load() {
+		stats.MemUsage = stats.MemUsageRaw
+	}
 	stats.MemLimit = statsJSON.MemoryStats.Limit
 	if stats.MemLimit > 0 {
 		stats.MemPercent = float64(stats.MemUsage) / float64(stats.MemLimit) * 100
 	}
+	stats.MemAnon, stats.MemFile, stats.MemShmem, stats.MemSwap = memoryBreakdown(statsJSON.MemoryStats)
+	stats.MemMaxUsage = statsJSON.MemoryStats.MaxUsage
+	stats.MemFailcnt = statsJSON.MemoryStats.Failcnt
+
+	// Network and block I/O stats
+	stats.NetRx, stats.NetTx = netTotals(statsJSON)
+	stats.BlockRead, stats.BlockWrite = blockTotals(statsJSON)
+
+	// Throughput since the previous sample
+	stats.NetRxRate = latest.rates.NetRx
+	stats.NetTxRate = latest.rates.NetTx
+	stats.BlockReadRate = latest.rates.BlockRead
+	stats.BlockWriteRate = latest.rates.BlockWrite
+
+	stats.BlockReadIOPS = latest.rates.ReadIOPS
+	stats.BlockWriteIOPS = latest.rates.WriteIOPS
+
+	// Per-device block I/O
+	stats.Devices = deviceStats(statsJSON, latest.rates.Devices, c.devices)
+
+	// Per-interface traffic
+	stats.Networks = interfaceStats(statsJSON, latest.rates.Interfaces)
+	if inspectErr == nil {
+		mapNetworks(stats.Networks, containerInfo, c.macs.reader(cont.ID))
+	}
 
-	// Network stats
-	for _, netStats := range statsJSON.Networks {
-		stats.NetRx += netStats.RxBytes
-		stats.NetTx += netStats.TxBytes
+	// PIDs
+	stats.PIDs = statsJSON.PidsStats.Current
+	if statsJSON.PidsStats.Limit != math.MaxUint64 {
+		stats.PIDsLimit = statsJSON.PidsStats.Limit
 	}
 
-	// Block I/O stats
-	for _, blkStats := range statsJSON.BlkioStats.IoServiceBytesRecursive {
-		switch blkStats.Op {
-		case "read", "Read":
-			stats.BlockRead += blkStats.Value
-		case "write", "Write":
-			stats.BlockWrite += blkStats.Value
-		}
+	return stats, sampleErr
+}
+
+// applyState copies health, exit and timing details from the inspected
+// container state
+func applyState(stats *ContainerStats, state *container.State) {
+	if state == nil {
+		return
+	}
+	stats.OOMKilled = state.OOMKilled
+	stats.ExitCode = state.ExitCode
+	stats.StartedAt = parseDockerTime(state.StartedAt)
+	stats.FinishedAt = parseDockerTime(state.FinishedAt)
+	if state.Health != nil && state.Health.Status != container.NoHealthcheck {
+		stats.Health = state.Health.Status
+		stats.HealthFailingStreak = state.Health.FailingStreak
 	}
+}
 
-	// PIDs
-	stats.PIDs = statsJSON.PidsStats.Current
+// parseDockerTime parses a timestamp from container inspect. Docker uses
+// the zero time 0001-01-01T00:00:00Z for events that did not happen yet.
+func parseDockerTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil || t.Year() <= 1 {
+		return time.Time{}
+	}
+	return t
+}
 
-	return stats, nil
+// restartPolicy formats a restart policy the way 'docker run' accepts it
+func restartPolicy(policy container.RestartPolicy) string {
+	if policy.Name == "" {
+		return string(container.RestartPolicyDisabled)
+	}
+	if policy.IsOnFailure() && policy.MaximumRetryCount > 0 {
+		return fmt.Sprintf("%s:%d", policy.Name, policy.MaximumRetryCount)
+	}
+	return string(policy.Name)
 }
 
 // calculateCPUPercent calculates the CPU usage percentage
 func calculateCPUPercent(stats *StatsJSON) float64 {
+	// The first sample of a stream carries no previous reading
+	if stats.PreCPUStats.SystemUsage == 0 {
+		return 0
+	}
+
 	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage - stats.PreCPUStats.CPUUsage.TotalUsage)
 	systemDelta := float64(stats.CPUStats.SystemUsage - stats.PreCPUStats.SystemUsage)
 
 	if systemDelta > 0 && cpuDelta > 0 {
-		cpuCount := float64(stats.CPUStats.OnlineCPUs)
-		if cpuCount == 0 {
-			cpuCount = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
-		}
-		if cpuCount == 0 {
-			cpuCount = 1
-		}
+		cpuCount := float64(onlineCPUs(stats))
 		return (cpuDelta / systemDelta) * cpuCount * 100
 	}
 	return 0
 }
 
+// calculatePerCPUPercent returns the usage of each host core relative to
+// one core. cgroup v2 does not report per-core usage, in which case nil is
+// returned.
+func calculatePerCPUPercent(stats *StatsJSON) []float64 {
+	cur := stats.CPUStats.CPUUsage.PercpuUsage
+	pre := stats.PreCPUStats.CPUUsage.PercpuUsage
+	if len(cur) == 0 || len(cur) != len(pre) || stats.PreCPUStats.SystemUsage == 0 {
+		return nil
+	}
+
+	systemDelta := float64(stats.CPUStats.SystemUsage - stats.PreCPUStats.SystemUsage)
+	if systemDelta <= 0 {
+		return nil
+	}
+	cpuCount := float64(onlineCPUs(stats))
+
+	percents := make([]float64, len(cur))
+	for i := range cur {
+		// A core counter going backwards means the cgroup was reset
+		if cur[i] > pre[i] {
+			percents[i] = float64(cur[i]-pre[i]) / systemDelta * cpuCount * 100
+		}
+	}
+	return percents
+}
+
+// onlineCPUs returns the number of host CPUs available to the container
+func onlineCPUs(stats *StatsJSON) uint32 {
+	if stats.CPUStats.OnlineCPUs > 0 {
+		return stats.CPUStats.OnlineCPUs
+	}
+	if n := len(stats.CPUStats.CPUUsage.PercpuUsage); n > 0 {
+		return uint32(n) // #nosec G115 - CPU counts are small
+	}
+	return 1
+}
+
+// normalizeCPUPercent converts a percentage of one host core into a
+// percentage of the container's CPU limit, or of all host cores when the
+// container is unlimited, so 100% means it uses everything it may
+func normalizeCPUPercent(hostPercent, limit float64, cpus uint32) float64 {
+	if limit > 0 {
+		return hostPercent / limit
+	}
+	if cpus > 0 {
+		return hostPercent / float64(cpus)
+	}
+	return hostPercent
+}
+
+// calculateThrottledPercent returns the share of CFS enforcement periods in
+// which the container was throttled between the previous and current sample
+func calculateThrottledPercent(stats *StatsJSON) float64 {
+	cur := stats.CPUStats.ThrottlingData
+	prev := stats.PreCPUStats.ThrottlingData
+	if cur.Periods <= prev.Periods || cur.ThrottledPeriods < prev.ThrottledPeriods {
+		return 0
+	}
+
+	periods := float64(cur.Periods - prev.Periods)
+	throttled := float64(cur.ThrottledPeriods - prev.ThrottledPeriods)
+	if throttled > periods {
+		return 100
+	}
+	return throttled / periods * 100
+}
+
+// calculateMemUsage returns memory usage the way the Docker CLI reports it,
+// i.e. without the inactive page cache the kernel can reclaim at any time
+func calculateMemUsage(mem MemoryStats) uint64 {
+	// cgroup v1
+	if v, ok := mem.Stats["total_inactive_file"]; ok && v < mem.Usage {
+		return mem.Usage - v
+	}
+	// cgroup v2
+	if v, ok := mem.Stats["inactive_file"]; ok && v < mem.Usage {
+		return mem.Usage - v
+	}
+	// Old cgroup v1 kernels without inactive_file accounting
+	if v, ok := mem.Stats["cache"]; ok && v < mem.Usage {
+		return mem.Usage - v
+	}
+	return mem.Usage
+}
+
+// memoryBreakdown splits memory usage into anonymous memory, file cache,
+// shared memory and swap. cgroup v1 reports hierarchical totals with a
+// "total_" prefix, cgroup v2 uses different key names and has no swap.
+func memoryBreakdown(mem MemoryStats) (anon, file, shmem, swap uint64) {
+	if _, isCgroup1 := mem.Stats["total_rss"]; isCgroup1 {
+		return mem.Stats["total_rss"], mem.Stats["total_cache"], mem.Stats["total_shmem"], mem.Stats["total_swap"]
+	}
+	if _, isCgroup1 := mem.Stats["rss"]; isCgroup1 {
+		return mem.Stats["rss"], mem.Stats["cache"], mem.Stats["shmem"], mem.Stats["swap"]
+	}
+	return mem.Stats["anon"], mem.Stats["file"], mem.Stats["shmem"], 0
+}
+
+// netTotals sums the received and sent bytes over all interfaces
+func netTotals(stats *StatsJSON) (rx, tx uint64) {
+	for _, netStats := range stats.Networks {
+		rx += netStats.RxBytes
+		tx += netStats.TxBytes
+	}
+	return rx, tx
+}
+
+// blockTotals sums the bytes read and written over all block devices
+func blockTotals(stats *StatsJSON) (read, write uint64) {
+	for _, blkStats := range stats.BlkioStats.IoServiceBytesRecursive {
+		switch r, w := blkioOp(blkStats.Op); {
+		case r:
+			read += blkStats.Value
+		case w:
+			write += blkStats.Value
+		}
+	}
+	return read, write
+}
+
 // trimContainerName removes the leading slash from container names
 func trimContainerName(names []string) string {
 	if len(names) == 0 {
@@ -309,6 +706,12 @@ func SortContainers(containers []ContainerStats, field SortField, ascending bool
 			less = (containers[i].BlockRead + containers[i].BlockWrite) < (containers[j].BlockRead + containers[j].BlockWrite)
 		case SortByImageSize:
 			less = containers[i].ImageSize < containers[j].ImageSize
+		case SortByNetRate:
+			less = (containers[i].NetRxRate + containers[i].NetTxRate) < (containers[j].NetRxRate + containers[j].NetTxRate)
+		case SortByBlockRate:
+			less = (containers[i].BlockReadRate + containers[i].BlockWriteRate) < (containers[j].BlockReadRate + containers[j].BlockWriteRate)
+		case SortByThrottle:
+			less = containers[i].ThrottledPercent < containers[j].ThrottledPercent
 		default:
 			less = containers[i].Name < containers[j].Name
 		}
@@ -348,20 +751,22 @@ func (c *Client) GetDockerInfo(ctx context.Context) (*DockerInfo, error) {
 		CPUs:              info.NCPU,
 		OSType:            info.OSType,
 		Architecture:      info.Architecture,
+		Hostname:          info.Name,
 	}, nil
 }
 
 // DockerInfo holds Docker daemon information
 type DockerInfo struct {
-	ServerVersion     string
-	ContainersTotal   int
-	ContainersRunning int
-	ContainersPaused  int
-	ContainersStopped int
-	ImagesTotal       int
-	TotalImageSize    int64
-	MemoryTotal       int64
-	CPUs              int
-	OSType            string
-	Architecture      string
+	ServerVersion     string `json:"server_version"`
+	ContainersTotal   int    `json:"containers_total"`
+	ContainersRunning int    `json:"containers_running"`
+	ContainersPaused  int    `json:"containers_paused"`
+	ContainersStopped int    `json:"containers_stopped"`
+	ImagesTotal       int    `json:"images_total"`
+	TotalImageSize    int64  `json:"total_image_size"`
+	MemoryTotal       int64  `json:"memory_total"`
+	CPUs              int    `json:"cpus"`
+	OSType            string `json:"os_type"`
+	Architecture      string `json:"architecture"`
+	Hostname          string `json:"hostname"` // Host name of the daemon's host
 }
@@ -1,9 +1,67 @@
 package docker
 
 import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
 )
 
+// loadFixture reads a stats payload captured from a Docker daemon
+func loadFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return data
+}
+
+// decodeFixture decodes a stats payload captured from a Docker daemon
+func decodeFixture(t *testing.T, name string) *StatsJSON {
+	t.Helper()
+	var stats StatsJSON
+	if err := json.Unmarshal(loadFixture(t, name), &stats); err != nil {
+		t.Fatalf("failed to decode fixture %s: %v", name, err)
+	}
+	return &stats
+}
+
+// fixtureStats runs a fixture through a client and returns the resulting
+// container statistics
+func fixtureStats(t *testing.T, name string, configure func(c *Client)) ContainerStats {
+	t.Helper()
+	payload := loadFixture(t, name)
+
+	api := newFakeAPI()
+	api.setContainers(container.Summary{ID: "0123456789abcdef", Names: []string{"/fixture"}, State: "running"})
+	api.stats["0123456789abcdef"] = func(w *io.PipeWriter) { w.Write(payload) }
+
+	c := newClient(api)
+	t.Cleanup(func() { c.Close() })
+	if configure != nil {
+		configure(c)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stats, err := c.GetContainerStats(ctx, false)
+	if err != nil {
+		t.Fatalf("GetContainerStats() error = %v", err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("GetContainerStats() returned %d containers; want 1", len(stats))
+	}
+	return stats[0]
+}
+
 func TestFormatBytes(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -139,6 +197,70 @@ func TestFormatMemUsage(t *testing.T) {
 	}
 }
 
+func TestFormatRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		rate     float64
+		expected string
+	}{
+		{"zero", 0, "0B/s"},
+		{"negative", -5, "0B/s"},
+		{"bytes", 512.7, "512B/s"},
+		{"megabytes", 1572864, "1.5MiB/s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FormatRate(tt.rate)
+			if result != tt.expected {
+				t.Errorf("FormatRate(%f) = %s; want %s", tt.rate, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCalculateRates(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sample := func(offset time.Duration, rx, tx, read, write uint64) *StatsJSON {
+		return &StatsJSON{
+			Read:     at.Add(offset),
+			Networks: map[string]NetStats{"eth0": {RxBytes: rx, TxBytes: tx}},
+			BlkioStats: BlkioStats{IoServiceBytesRecursive: []BlkioStatEntry{
+				{Op: "read", Value: read},
+				{Op: "write", Value: write},
+			}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		prev     *StatsJSON
+		cur      *StatsJSON
+		expected ioRates
+	}{
+		{"no previous sample", nil, sample(0, 100, 100, 100, 100), ioRates{}},
+		{"two seconds apart", sample(0, 1000, 2000, 0, 4096), sample(2*time.Second, 3000, 2000, 2048, 8192),
+			ioRates{NetRx: 1000, NetTx: 0, BlockRead: 1024, BlockWrite: 2048,
+				Interfaces: map[string]interfaceRates{"eth0": {Rx: 1000, Tx: 0}},
+				Devices:    map[string]deviceRates{"0:0": {Read: 1024, Write: 2048}}}},
+		{"counter reset", sample(0, 5000, 5000, 5000, 5000), sample(time.Second, 100, 6000, 10, 5000),
+			ioRates{NetRx: 0, NetTx: 1000, BlockRead: 0, BlockWrite: 0,
+				Interfaces: map[string]interfaceRates{"eth0": {Rx: 0, Tx: 1000}},
+				Devices:    map[string]deviceRates{"0:0": {}}}},
+		{"gap too long", sample(0, 0, 0, 0, 0), sample(time.Hour, 1<<40, 0, 0, 0), ioRates{}},
+		{"out of order", sample(time.Second, 0, 0, 0, 0), sample(0, 100, 0, 0, 0), ioRates{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := calculateRates(tt.prev, tt.cur)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("calculateRates() = %+v; want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestTrimContainerName(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -194,4 +316,327 @@ func TestSortContainers(t *testing.T) {
 			t.Errorf("Sort by memory descending failed: %v", c)
 		}
 	})
+
+	t.Run("sort by network rate descending", func(t *testing.T) {
+		c := []ContainerStats{
+			{Name: "alpha", NetRxRate: 10, NetTxRate: 10},
+			{Name: "beta", NetRxRate: 500},
+			{Name: "gamma", NetTxRate: 100},
+		}
+		SortContainers(c, SortByNetRate, false)
+		if c[0].Name != "beta" || c[1].Name != "gamma" || c[2].Name != "alpha" {
+			t.Errorf("Sort by network rate descending failed: %v", c)
+		}
+	})
+
+	t.Run("sort by block rate ascending", func(t *testing.T) {
+		c := []ContainerStats{
+			{Name: "alpha", BlockWriteRate: 300},
+			{Name: "beta", BlockReadRate: 100},
+			{Name: "gamma", BlockReadRate: 100, BlockWriteRate: 100},
+		}
+		SortContainers(c, SortByBlockRate, true)
+		if c[0].Name != "beta" || c[1].Name != "gamma" || c[2].Name != "alpha" {
+			t.Errorf("Sort by block rate ascending failed: %v", c)
+		}
+	})
+}
+
+func TestCalculateMemUsage(t *testing.T) {
+	tests := []struct {
+		name     string
+		mem      MemoryStats
+		expected uint64
+	}{
+		{"cgroup v1", MemoryStats{Usage: 1000, Stats: map[string]uint64{"total_inactive_file": 300, "inactive_file": 100}}, 700},
+		{"cgroup v2", MemoryStats{Usage: 1000, Stats: map[string]uint64{"inactive_file": 400}}, 600},
+		{"old cgroup v1 with cache only", MemoryStats{Usage: 1000, Stats: map[string]uint64{"cache": 250}}, 750},
+		{"no stats", MemoryStats{Usage: 1000}, 1000},
+		{"inactive file larger than usage", MemoryStats{Usage: 1000, Stats: map[string]uint64{"inactive_file": 2000}}, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := calculateMemUsage(tt.mem)
+			if result != tt.expected {
+				t.Errorf("calculateMemUsage() = %d; want %d", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMemoryUsageFixtures(t *testing.T) {
+	tests := []struct {
+		fixture     string
+		usage       uint64
+		raw         uint64
+		percent     float64
+		rawPercent  float64
+		description string
+	}{
+		{"stats_cgroup_v1.json", 83886080, 104857600, 15.625, 19.53125, "usage minus total_inactive_file"},
+		{"stats_cgroup_v2.json", 157286400, 209715200, 14.6484375, 19.53125, "usage minus inactive_file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			mem := decodeFixture(t, tt.fixture).MemoryStats
+			if got := calculateMemUsage(mem); got != tt.usage {
+				t.Errorf("calculateMemUsage() = %d; want %d (%s)", got, tt.usage, tt.description)
+			}
+
+			stats := fixtureStats(t, tt.fixture, nil)
+			if stats.MemUsage != tt.usage || stats.MemUsageRaw != tt.raw {
+				t.Errorf("MemUsage, MemUsageRaw = %d, %d; want %d, %d", stats.MemUsage, stats.MemUsageRaw, tt.usage, tt.raw)
+			}
+			if stats.MemPercent != tt.percent {
+				t.Errorf("MemPercent = %f; want %f", stats.MemPercent, tt.percent)
+			}
+
+			raw := fixtureStats(t, tt.fixture, func(c *Client) { c.SetRawMemory(true) })
+			if raw.MemUsage != tt.raw || raw.MemPercent != tt.rawPercent {
+				t.Errorf("raw MemUsage, MemPercent = %d, %f; want %d, %f", raw.MemUsage, raw.MemPercent, tt.raw, tt.rawPercent)
+			}
+		})
+	}
+}
+
+func TestMemoryBreakdownFixtures(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		anon     uint64
+		file     uint64
+		shmem    uint64
+		swap     uint64
+		maxUsage uint64
+		failcnt  uint64
+	}{
+		{"stats_cgroup_v1.json", 52428800, 41943040, 2097152, 1048576, 157286400, 3},
+		{"stats_cgroup_v2.json", 94371840, 104857600, 4194304, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			stats := fixtureStats(t, tt.fixture, nil)
+			if stats.MemAnon != tt.anon || stats.MemFile != tt.file || stats.MemShmem != tt.shmem || stats.MemSwap != tt.swap {
+				t.Errorf("anon, file, shmem, swap = %d, %d, %d, %d; want %d, %d, %d, %d",
+					stats.MemAnon, stats.MemFile, stats.MemShmem, stats.MemSwap, tt.anon, tt.file, tt.shmem, tt.swap)
+			}
+			if stats.MemMaxUsage != tt.maxUsage || stats.MemFailcnt != tt.failcnt {
+				t.Errorf("max usage, failcnt = %d, %d; want %d, %d", stats.MemMaxUsage, stats.MemFailcnt, tt.maxUsage, tt.failcnt)
+			}
+		})
+	}
+}
+
+func TestCalculateThrottledPercent(t *testing.T) {
+	throttling := func(prevPeriods, prevThrottled, periods, throttled uint64) *StatsJSON {
+		return &StatsJSON{
+			CPUStats:    CPUStats{ThrottlingData: ThrottlingData{Periods: periods, ThrottledPeriods: throttled}},
+			PreCPUStats: CPUStats{ThrottlingData: ThrottlingData{Periods: prevPeriods, ThrottledPeriods: prevThrottled}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		stats    *StatsJSON
+		expected float64
+	}{
+		{"no limit", throttling(0, 0, 0, 0), 0},
+		{"not throttled", throttling(100, 5, 200, 5), 0},
+		{"quarter throttled", throttling(100, 5, 200, 30), 25},
+		{"fully throttled", throttling(100, 5, 110, 15), 100},
+		{"counter reset", throttling(500, 50, 10, 2), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := calculateThrottledPercent(tt.stats)
+			if result != tt.expected {
+				t.Errorf("calculateThrottledPercent() = %f; want %f", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestThrottlingFixtures(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		percent  float64
+		periods  uint64
+		duration time.Duration
+	}{
+		{"stats_cgroup_v1.json", 50, 20, 500 * time.Millisecond},
+		{"stats_cgroup_v2.json", 30, 250, 12 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			stats := fixtureStats(t, tt.fixture, nil)
+			if stats.ThrottledPercent != tt.percent {
+				t.Errorf("ThrottledPercent = %f; want %f", stats.ThrottledPercent, tt.percent)
+			}
+			if stats.ThrottledPeriods != tt.periods || stats.ThrottledTime != tt.duration {
+				t.Errorf("ThrottledPeriods, ThrottledTime = %d, %s; want %d, %s",
+					stats.ThrottledPeriods, stats.ThrottledTime, tt.periods, tt.duration)
+			}
+		})
+	}
+}
+
+func TestNormalizeCPUPercent(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     float64
+		limit    float64
+		cpus     uint32
+		expected float64
+	}{
+		{"half CPU limit", 25, 0.5, 8, 50},
+		{"two CPU limit", 150, 2, 8, 75},
+		{"unlimited uses host cores", 200, 0, 4, 50},
+		{"unknown cores", 30, 0, 0, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := normalizeCPUPercent(tt.host, tt.limit, tt.cpus)
+			if result != tt.expected {
+				t.Errorf("normalizeCPUPercent(%f, %f, %d) = %f; want %f", tt.host, tt.limit, tt.cpus, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCPUModeFixtures(t *testing.T) {
+	limited := func(c *Client) {
+		c.SetCPUMode(CPUModeLimit)
+		c.cli.(*fakeAPI).inspects["0123456789abcdef"] = container.InspectResponse{
+			ContainerJSONBase: &container.ContainerJSONBase{
+				HostConfig: &container.HostConfig{Resources: container.Resources{NanoCPUs: 500_000_000}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		configure func(c *Client)
+		percent   float64
+	}{
+		{"host", nil, 50},
+		{"limit without CPU limit", func(c *Client) { c.SetCPUMode(CPUModeLimit) }, 25},
+		{"limit with half a CPU", limited, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := fixtureStats(t, "stats_cgroup_v1.json", tt.configure)
+			if stats.CPUPercent != tt.percent {
+				t.Errorf("CPUPercent = %f; want %f", stats.CPUPercent, tt.percent)
+			}
+			if stats.CPUPercentHost != 50 || stats.OnlineCPUs != 2 {
+				t.Errorf("CPUPercentHost, OnlineCPUs = %f, %d; want 50, 2", stats.CPUPercentHost, stats.OnlineCPUs)
+			}
+		})
+	}
+}
+
+func TestParseCPUMode(t *testing.T) {
+	for _, mode := range []CPUMode{CPUModeHost, CPUModeLimit} {
+		parsed, err := ParseCPUMode(mode.String())
+		if err != nil || parsed != mode {
+			t.Errorf("ParseCPUMode(%q) = %v, %v; want %v", mode.String(), parsed, err, mode)
+		}
+	}
+	if _, err := ParseCPUMode("cores"); err == nil {
+		t.Error("ParseCPUMode(\"cores\") error = nil; want error")
+	}
+}
+
+func TestPerCPUFixtures(t *testing.T) {
+	pinned := func(c *Client) {
+		c.cli.(*fakeAPI).inspects["0123456789abcdef"] = container.InspectResponse{
+			ContainerJSONBase: &container.ContainerJSONBase{
+				HostConfig: &container.HostConfig{Resources: container.Resources{CpusetCpus: "0-1"}},
+			},
+		}
+	}
+
+	v1 := fixtureStats(t, "stats_cgroup_v1.json", pinned)
+	if len(v1.PerCPUPercent) != 2 || v1.PerCPUPercent[0] != 30 || v1.PerCPUPercent[1] != 20 {
+		t.Errorf("PerCPUPercent = %v; want [30 20]", v1.PerCPUPercent)
+	}
+	if v1.CPUSet != "0-1" {
+		t.Errorf("CPUSet = %q; want 0-1", v1.CPUSet)
+	}
+
+	// cgroup v2 does not report per-core usage
+	v2 := fixtureStats(t, "stats_cgroup_v2.json", nil)
+	if v2.PerCPUPercent != nil || v2.CPUSet != "" {
+		t.Errorf("PerCPUPercent, CPUSet = %v, %q; want nil, empty", v2.PerCPUPercent, v2.CPUSet)
+	}
+}
+
+func TestFormatUptime(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        time.Duration
+		expected string
+	}{
+		{"not running", 0, "-"},
+		{"seconds", 45 * time.Second, "45s"},
+		{"minutes", 12*time.Minute + 30*time.Second, "12m"},
+		{"hours", 5*time.Hour + 12*time.Minute, "5h12m"},
+		{"days", 76 * time.Hour, "3d4h"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FormatUptime(tt.d)
+			if result != tt.expected {
+				t.Errorf("FormatUptime(%s) = %s; want %s", tt.d, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLifecycleFromInspect(t *testing.T) {
+	started := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	stats := fixtureStats(t, "stats_cgroup_v1.json", func(c *Client) {
+		c.cli.(*fakeAPI).inspects["0123456789abcdef"] = container.InspectResponse{
+			ContainerJSONBase: &container.ContainerJSONBase{
+				RestartCount: 3,
+				State: &container.State{
+					Status:     "running",
+					OOMKilled:  true,
+					ExitCode:   137,
+					StartedAt:  started.Format(time.RFC3339Nano),
+					FinishedAt: "0001-01-01T00:00:00Z",
+					Health:     &container.Health{Status: container.Unhealthy, FailingStreak: 4},
+				},
+				HostConfig: &container.HostConfig{
+					RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyOnFailure, MaximumRetryCount: 5},
+				},
+			},
+		}
+	})
+
+	if stats.PIDs != 12 || stats.PIDsLimit != 100 {
+		t.Errorf("PIDs, PIDsLimit = %d, %d; want 12, 100", stats.PIDs, stats.PIDsLimit)
+	}
+	if stats.Health != "unhealthy" || stats.HealthFailingStreak != 4 {
+		t.Errorf("Health, HealthFailingStreak = %q, %d; want unhealthy, 4", stats.Health, stats.HealthFailingStreak)
+	}
+	if stats.RestartCount != 3 || stats.RestartPolicy != "on-failure:5" {
+		t.Errorf("RestartCount, RestartPolicy = %d, %q; want 3, on-failure:5", stats.RestartCount, stats.RestartPolicy)
+	}
+	if !stats.OOMKilled || stats.ExitCode != 137 {
+		t.Errorf("OOMKilled, ExitCode = %v, %d; want true, 137", stats.OOMKilled, stats.ExitCode)
+	}
+	if !stats.StartedAt.Equal(started) || !stats.FinishedAt.IsZero() {
+		t.Errorf("StartedAt, FinishedAt = %s, %s; want %s, zero", stats.StartedAt, stats.FinishedAt, started)
+	}
+	if stats.Uptime() <= 0 {
+		t.Errorf("Uptime() = %s; want positive", stats.Uptime())
+	}
 }
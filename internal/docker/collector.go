@@ -0,0 +1,197 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/client"
+)
+
+const (
+	// streamRetryDelay is how long a stats stream waits before resubscribing
+	// after the daemon closed it or returned an error
+	streamRetryDelay = time.Second
+
+	// staleAfter is how old the latest sample may get before the stats of
+	// a container are reported as stale; streams deliver one per second
+	staleAfter = 10 * time.Second
+)
+
+// statsCollector keeps one streaming stats subscription per running
+// container and caches the most recent sample of each, so that callers
+// can read current statistics without waiting on the daemon
+type statsCollector struct {
+	cli client.APIClient
+	ctx context.Context
+
+	mu      sync.Mutex
+	streams map[string]*statsStream
+}
+
+// statsStream is a single long-lived stats subscription
+type statsStream struct {
+	cancel context.CancelFunc
+	ready  chan struct{} // closed once the first usable sample arrived or the first subscription ended
+	once   sync.Once
+
+	mu     sync.RWMutex
+	latest sample
+	err    error // why the last subscription failed, nil while samples arrive
+}
+
+// sample is one decoded stats frame together with the values derived from
+// the previous frame of the same stream
+type sample struct {
+	stats    *StatsJSON
+	rates    ioRates
+	received time.Time
+}
+
+// newStatsCollector creates a collector whose streams live until ctx is done
+func newStatsCollector(ctx context.Context, cli client.APIClient) *statsCollector {
+	return &statsCollector{
+		cli:     cli,
+		ctx:     ctx,
+		streams: make(map[string]*statsStream),
+	}
+}
+
+// sync starts streams for containers that are not yet watched and stops
+// streams for containers that are no longer in ids. It returns the streams
+// that were started by this call.
+func (sc *statsCollector) sync(ids []string) []*statsStream {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	wanted := make(map[string]bool, len(ids))
+	var started []*statsStream
+	for _, id := range ids {
+		wanted[id] = true
+		if _, ok := sc.streams[id]; ok {
+			continue
+		}
+		ctx, cancel := context.WithCancel(sc.ctx)
+		st := &statsStream{cancel: cancel, ready: make(chan struct{})}
+		sc.streams[id] = st
+		started = append(started, st)
+		go st.run(ctx, sc.cli, id)
+	}
+
+	for id, st := range sc.streams {
+		if !wanted[id] {
+			st.cancel()
+			delete(sc.streams, id)
+		}
+	}
+
+	return started
+}
+
+// latest returns the most recent sample for a container; its stats are nil
+// when there is none. The error explains a missing or stale sample; a stale
+// sample is returned together with an ErrStaleStats error.
+func (sc *statsCollector) latest(id string) (sample, error) {
+	sc.mu.Lock()
+	st, ok := sc.streams[id]
+	sc.mu.Unlock()
+	if !ok {
+		return sample{}, ErrNoSample
+	}
+
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	if st.latest.stats == nil {
+		if st.err != nil {
+			return sample{}, st.err
+		}
+		return sample{}, ErrNoSample
+	}
+	if age := time.Since(st.latest.received); age > staleAfter {
+		err := fmt.Errorf("%w: last sample %s ago", ErrStaleStats, age.Round(time.Second))
+		if st.err != nil {
+			err = fmt.Errorf("%w: last sample %s ago: %v", ErrStaleStats, age.Round(time.Second), st.err)
+		}
+		return st.latest, err
+	}
+	return st.latest, nil
+}
+
+// wait blocks until every stream has delivered its first sample or ctx is done
+func (sc *statsCollector) wait(ctx context.Context, streams []*statsStream) {
+	for _, st := range streams {
+		select {
+		case <-st.ready:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// run reads samples from the daemon until ctx is cancelled, resubscribing
+// whenever the stream ends
+func (st *statsStream) run(ctx context.Context, cli client.APIClient, id string) {
+	for {
+		st.read(ctx, cli, id)
+		st.once.Do(func() { close(st.ready) })
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(streamRetryDelay):
+		}
+	}
+}
+
+// read consumes one stats stream until it ends. Rates are only computed
+// between frames of the same stream, so a resubscription after a restart
+// never compares counters across container lifetimes.
+func (st *statsStream) read(ctx context.Context, cli client.APIClient, id string) {
+	resp, err := cli.ContainerStats(ctx, id, true)
+	if err != nil {
+		st.fail(fmt.Errorf("stats request failed: %w", err))
+		return
+	}
+	defer resp.Body.Close() //nolint:errcheck // intentionally ignoring close error
+
+	decoder := json.NewDecoder(resp.Body)
+	var prev *StatsJSON
+	for {
+		frame := &StatsJSON{}
+		if err := decoder.Decode(frame); err != nil {
+			if errors.Is(err, io.EOF) {
+				st.fail(errors.New("stats stream closed by daemon"))
+			} else if ctx.Err() == nil {
+				st.fail(fmt.Errorf("failed to decode stats: %w", err))
+			}
+			return
+		}
+
+		st.mu.Lock()
+		st.latest = sample{
+			stats:    frame,
+			rates:    calculateRates(prev, frame),
+			received: time.Now(),
+		}
+		st.err = nil
+		st.mu.Unlock()
+		prev = frame
+
+		// The first frame of a stream has no previous CPU sample to diff
+		// against, so it is not usable for CPU percentages yet
+		if frame.PreCPUStats.SystemUsage > 0 {
+			st.once.Do(func() { close(st.ready) })
+		}
+	}
+}
+
+// fail records why the stream stopped delivering samples
+func (st *statsStream) fail(err error) {
+	st.mu.Lock()
+	st.err = err
+	st.mu.Unlock()
+}
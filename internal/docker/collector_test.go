@@ -0,0 +1,143 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+const (
+	firstFrame  = `{"cpu_stats":{"cpu_usage":{"total_usage":1000},"system_cpu_usage":10000,"online_cpus":2},"memory_stats":{"usage":100,"limit":1000}}`
+	secondFrame = `{"cpu_stats":{"cpu_usage":{"total_usage":2000},"system_cpu_usage":20000,"online_cpus":2},"precpu_stats":{"cpu_usage":{"total_usage":1000},"system_cpu_usage":10000,"online_cpus":2},"memory_stats":{"usage":200,"limit":1000}}`
+)
+
+func TestGetContainerStatsFromStream(t *testing.T) {
+	api := newFakeAPI()
+	api.containers = []container.Summary{
+		{ID: "aaaaaaaaaaaaaaaa", Names: []string{"/web"}, State: "running"},
+		{ID: "bbbbbbbbbbbbbbbb", Names: []string{"/old"}, State: "exited"},
+	}
+	api.stats["aaaaaaaaaaaaaaaa"] = func(w *io.PipeWriter) {
+		w.Write([]byte(firstFrame + "\n" + secondFrame + "\n"))
+	}
+
+	c := newClient(api)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stats, err := c.GetContainerStats(ctx, true)
+	if err != nil {
+		t.Fatalf("GetContainerStats() error = %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("GetContainerStats() returned %d containers; want 2", len(stats))
+	}
+	SortContainers(stats, SortByName, false)
+
+	web := stats[0]
+	if web.Name != "web" {
+		t.Fatalf("first container = %s; want web", web.Name)
+	}
+	if web.CPUPercent != 20 {
+		t.Errorf("CPUPercent = %f; want 20", web.CPUPercent)
+	}
+	if web.MemUsage != 200 {
+		t.Errorf("MemUsage = %d; want 200", web.MemUsage)
+	}
+
+	// A second refresh must be served from the cached stream
+	if _, err := c.GetContainerStats(ctx, true); err != nil {
+		t.Fatalf("GetContainerStats() error = %v", err)
+	}
+	if n := api.calls("aaaaaaaaaaaaaaaa"); n != 1 {
+		t.Errorf("ContainerStats called %d times; want 1", n)
+	}
+	if n := api.calls("bbbbbbbbbbbbbbbb"); n != 0 {
+		t.Errorf("ContainerStats called %d times for stopped container; want 0", n)
+	}
+}
+
+func TestStatsCollectorStopsRemovedStreams(t *testing.T) {
+	api := newFakeAPI()
+	api.stats["one"] = func(w *io.PipeWriter) { w.Write([]byte(secondFrame)) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sc := newStatsCollector(ctx, api)
+	sc.wait(ctx, sc.sync([]string{"one"}))
+	if _, err := sc.latest("one"); err != nil {
+		t.Fatalf("latest() for a watched container error = %v", err)
+	}
+
+	sc.sync(nil)
+	if latest, err := sc.latest("one"); latest.stats != nil || !errors.Is(err, ErrNoSample) {
+		t.Errorf("latest() for a removed container = %v, %v; want nil, ErrNoSample", latest.stats, err)
+	}
+}
+
+func TestGetContainerStatsReportsFailures(t *testing.T) {
+	api := newFakeAPI()
+	api.containers = []container.Summary{
+		{ID: "aaaaaaaaaaaaaaaa", Names: []string{"/web"}, State: "running"},
+		{ID: "cccccccccccccccc", Names: []string{"/broken"}, State: "running"},
+	}
+	api.stats["aaaaaaaaaaaaaaaa"] = func(w *io.PipeWriter) { w.Write([]byte(secondFrame)) }
+
+	c := newClient(api)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stats, err := c.GetContainerStats(ctx, false)
+	if len(stats) != 2 {
+		t.Fatalf("GetContainerStats() returned %d containers; want 2", len(stats))
+	}
+
+	var partial *PartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("GetContainerStats() error = %v; want *PartialError", err)
+	}
+	if !IsPartial(err) {
+		t.Error("IsPartial() = false; want true")
+	}
+	if partial.Total != 2 || len(partial.Failures) != 1 || partial.Failures[0].Name != "broken" {
+		t.Fatalf("PartialError = %+v; want one failure for broken", partial)
+	}
+
+	for _, s := range stats {
+		if s.Name == "broken" && (!s.Unavailable() || s.Error == "") {
+			t.Errorf("broken container not marked unavailable: %+v", s)
+		}
+		if s.Name == "web" && s.Unavailable() {
+			t.Errorf("web container marked unavailable: %s", s.Error)
+		}
+	}
+}
+
+func TestStatsCollectorStale(t *testing.T) {
+	st := &statsStream{latest: sample{stats: &StatsJSON{}, received: time.Now().Add(-time.Minute)}}
+	sc := &statsCollector{streams: map[string]*statsStream{"old": st}}
+
+	latest, err := sc.latest("old")
+	if latest.stats == nil {
+		t.Fatal("latest() dropped the stale sample")
+	}
+	if !errors.Is(err, ErrStaleStats) {
+		t.Errorf("latest() error = %v; want ErrStaleStats", err)
+	}
+}
+
+func TestCalculateCPUPercentFirstSample(t *testing.T) {
+	stats := &StatsJSON{CPUStats: CPUStats{CPUUsage: CPUUsage{TotalUsage: 5000}, SystemUsage: 10000, OnlineCPUs: 4}}
+	if got := calculateCPUPercent(stats); got != 0 {
+		t.Errorf("calculateCPUPercent() without previous sample = %f; want 0", got)
+	}
+}
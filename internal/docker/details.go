@@ -0,0 +1,202 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ContainerDetails holds the configuration and health history of a
+// container for the detail view
+type ContainerDetails struct {
+	ID          string
+	Name        string
+	Image       string
+	Command     []string // Entrypoint followed by the command
+	WorkingDir  string
+	User        string
+	Env         []string
+	Mounts      []MountInfo
+	Ports       []PortInfo
+	Networks    []NetworkInfo
+	Labels      map[string]string
+	Limits      ResourceLimits
+	Healthcheck []string       // Health check test command; empty without a health check
+	HealthLog   []HealthResult // Latest health check results, oldest first
+}
+
+// MountInfo describes a volume, bind or tmpfs mount
+type MountInfo struct {
+	Type        string
+	Source      string
+	Destination string
+	Mode        string
+	ReadWrite   bool
+}
+
+// PortInfo describes an exposed port and where it is published
+type PortInfo struct {
+	ContainerPort string // e.g. 80/tcp
+	HostIP        string
+	HostPort      string // Empty when the port is not published
+}
+
+// NetworkInfo describes the container's endpoint in a Docker network
+type NetworkInfo struct {
+	Name       string
+	IPAddress  string
+	Gateway    string
+	MacAddress string
+	Aliases    []string
+}
+
+// ResourceLimits holds the resource limits of a container; zero values
+// mean unlimited
+type ResourceLimits struct {
+	CPUs              float64
+	CPUShares         int64
+	CPUSet            string
+	Memory            int64
+	MemoryReservation int64
+	MemorySwap        int64 // -1 for unlimited swap
+	PidsLimit         int64
+}
+
+// HealthResult is the outcome of one health check run
+type HealthResult struct {
+	Start    time.Time
+	End      time.Time
+	ExitCode int
+	Output   string
+}
+
+// ProcessList is the process table of a container as reported by the
+// container top API. When running on the Docker host it is extended with
+// %CPU and RSS columns from /proc, busiest processes first.
+type ProcessList struct {
+	Titles    []string
+	Processes [][]string
+	HostStats bool // The %CPU and RSS columns were added
+}
+
+// GetContainerDetails returns the configuration and health history of a
+// container. It always inspects the container, bypassing the cache, so the
+// health log is current.
+func (c *Client) GetContainerDetails(ctx context.Context, id string) (ContainerDetails, error) {
+	info, err := c.cli.ContainerInspect(ctx, id)
+	if err != nil {
+		return ContainerDetails{}, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	return containerDetails(info), nil
+}
+
+// GetProcesses returns the processes running in a container
+func (c *Client) GetProcesses(ctx context.Context, id string) (ProcessList, error) {
+	top, err := c.cli.ContainerTop(ctx, id, nil)
+	if err != nil {
+		return ProcessList{}, fmt.Errorf("failed to list processes: %w", err)
+	}
+	list := ProcessList{Titles: top.Titles, Processes: top.Processes}
+	list.HostStats = enrichProcesses(&list, id, c.procs)
+	return list, nil
+}
+
+// containerDetails extracts the detail view data from an inspect result
+func containerDetails(info container.InspectResponse) ContainerDetails {
+	var d ContainerDetails
+
+	if info.ContainerJSONBase != nil {
+		d.ID = info.ID
+		d.Name = strings.TrimPrefix(info.Name, "/")
+		if hc := info.HostConfig; hc != nil {
+			d.Limits = ResourceLimits{
+				CPUShares:         hc.CPUShares,
+				CPUSet:            hc.CpusetCpus,
+				Memory:            hc.Memory,
+				MemoryReservation: hc.MemoryReservation,
+				MemorySwap:        hc.MemorySwap,
+			}
+			if hc.NanoCPUs > 0 {
+				d.Limits.CPUs = float64(hc.NanoCPUs) / 1e9
+			} else if hc.CPUQuota > 0 && hc.CPUPeriod > 0 {
+				d.Limits.CPUs = float64(hc.CPUQuota) / float64(hc.CPUPeriod)
+			}
+			if hc.PidsLimit != nil && *hc.PidsLimit > 0 {
+				d.Limits.PidsLimit = *hc.PidsLimit
+			}
+		}
+		if info.State != nil && info.State.Health != nil {
+			for _, r := range info.State.Health.Log {
+				if r != nil {
+					d.HealthLog = append(d.HealthLog, HealthResult{Start: r.Start, End: r.End, ExitCode: r.ExitCode, Output: r.Output})
+				}
+			}
+		}
+	}
+
+	if cfg := info.Config; cfg != nil {
+		d.Image = cfg.Image
+		d.Command = append(append([]string{}, cfg.Entrypoint...), cfg.Cmd...)
+		d.WorkingDir = cfg.WorkingDir
+		d.User = cfg.User
+		d.Env = cfg.Env
+		d.Labels = cfg.Labels
+		if cfg.Healthcheck != nil && len(cfg.Healthcheck.Test) > 0 && cfg.Healthcheck.Test[0] != "NONE" {
+			d.Healthcheck = cfg.Healthcheck.Test
+		}
+	}
+
+	for _, m := range info.Mounts {
+		d.Mounts = append(d.Mounts, MountInfo{
+			Type:        string(m.Type),
+			Source:      m.Source,
+			Destination: m.Destination,
+			Mode:        m.Mode,
+			ReadWrite:   m.RW,
+		})
+	}
+
+	if ns := info.NetworkSettings; ns != nil {
+		for port, bindings := range ns.Ports {
+			if len(bindings) == 0 {
+				d.Ports = append(d.Ports, PortInfo{ContainerPort: string(port)})
+			}
+			for _, b := range bindings {
+				d.Ports = append(d.Ports, PortInfo{ContainerPort: string(port), HostIP: b.HostIP, HostPort: b.HostPort})
+			}
+		}
+		for name, ep := range ns.Networks {
+			n := NetworkInfo{Name: name}
+			if ep != nil {
+				n.IPAddress = ep.IPAddress
+				n.Gateway = ep.Gateway
+				n.MacAddress = ep.MacAddress
+				n.Aliases = ep.Aliases
+			}
+			d.Networks = append(d.Networks, n)
+		}
+	}
+	sort.Slice(d.Ports, func(i, j int) bool {
+		if d.Ports[i].ContainerPort != d.Ports[j].ContainerPort {
+			return d.Ports[i].ContainerPort < d.Ports[j].ContainerPort
+		}
+		return d.Ports[i].HostIP < d.Ports[j].HostIP
+	})
+	sort.Slice(d.Networks, func(i, j int) bool { return d.Networks[i].Name < d.Networks[j].Name })
+
+	return d
+}
+
+// SortedLabels returns the label keys in alphabetical order
+func (d ContainerDetails) SortedLabels() []string {
+	keys := make([]string, 0, len(d.Labels))
+	for k := range d.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
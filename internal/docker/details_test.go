@@ -0,0 +1,105 @@
+package docker
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+)
+
+func TestGetContainerDetails(t *testing.T) {
+	pids := int64(200)
+	checked := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	api := newFakeAPI()
+	api.inspects["web"] = container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			ID:   "0123456789abcdef",
+			Name: "/web",
+			State: &container.State{Health: &container.Health{Log: []*container.HealthcheckResult{
+				{Start: checked, End: checked.Add(time.Second), ExitCode: 1, Output: "connection refused"},
+			}}},
+			HostConfig: &container.HostConfig{Resources: container.Resources{
+				NanoCPUs: 1_500_000_000, Memory: 512 << 20, PidsLimit: &pids,
+			}},
+		},
+		Config: &container.Config{
+			Image:       "nginx:alpine",
+			Entrypoint:  []string{"/docker-entrypoint.sh"},
+			Cmd:         []string{"nginx", "-g", "daemon off;"},
+			Env:         []string{"PATH=/usr/bin"},
+			Labels:      map[string]string{"b": "2", "a": "1"},
+			Healthcheck: &container.HealthConfig{Test: []string{"CMD-SHELL", "curl -f localhost"}},
+		},
+		Mounts: []container.MountPoint{{Type: "bind", Source: "/srv", Destination: "/usr/share/nginx/html", RW: false}},
+		NetworkSettings: &container.NetworkSettings{
+			NetworkSettingsBase: container.NetworkSettingsBase{Ports: nat.PortMap{
+				"443/tcp": nil,
+				"80/tcp":  []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "8080"}},
+			}},
+			Networks: map[string]*network.EndpointSettings{"frontend": {IPAddress: "172.18.0.2"}},
+		},
+	}
+
+	c := newClient(api)
+	defer c.Close()
+
+	d, err := c.GetContainerDetails(context.Background(), "web")
+	if err != nil {
+		t.Fatalf("GetContainerDetails() error = %v", err)
+	}
+
+	if d.Name != "web" || d.Image != "nginx:alpine" {
+		t.Errorf("Name, Image = %q, %q; want web, nginx:alpine", d.Name, d.Image)
+	}
+	if want := []string{"/docker-entrypoint.sh", "nginx", "-g", "daemon off;"}; !reflect.DeepEqual(d.Command, want) {
+		t.Errorf("Command = %q; want %q", d.Command, want)
+	}
+	if d.Limits.CPUs != 1.5 || d.Limits.Memory != 512<<20 || d.Limits.PidsLimit != 200 {
+		t.Errorf("Limits = %+v; want 1.5 CPUs, 512MiB, 200 PIDs", d.Limits)
+	}
+	wantPorts := []PortInfo{{ContainerPort: "443/tcp"}, {ContainerPort: "80/tcp", HostIP: "0.0.0.0", HostPort: "8080"}}
+	if !reflect.DeepEqual(d.Ports, wantPorts) {
+		t.Errorf("Ports = %+v; want %+v", d.Ports, wantPorts)
+	}
+	if len(d.Networks) != 1 || d.Networks[0].Name != "frontend" || d.Networks[0].IPAddress != "172.18.0.2" {
+		t.Errorf("Networks = %+v; want frontend at 172.18.0.2", d.Networks)
+	}
+	if len(d.Mounts) != 1 || d.Mounts[0].ReadWrite || d.Mounts[0].Source != "/srv" {
+		t.Errorf("Mounts = %+v; want read-only bind of /srv", d.Mounts)
+	}
+	if len(d.HealthLog) != 1 || d.HealthLog[0].Output != "connection refused" || len(d.Healthcheck) != 2 {
+		t.Errorf("Healthcheck, HealthLog = %q, %+v; want one failed check", d.Healthcheck, d.HealthLog)
+	}
+	if keys := d.SortedLabels(); !reflect.DeepEqual(keys, []string{"a", "b"}) {
+		t.Errorf("SortedLabels() = %q; want [a b]", keys)
+	}
+
+	if _, err := c.GetContainerDetails(context.Background(), "missing"); err == nil {
+		t.Error("GetContainerDetails() for a missing container error = nil; want error")
+	}
+}
+
+func TestGetProcesses(t *testing.T) {
+	api := newFakeAPI()
+	api.tops["web"] = container.TopResponse{
+		Titles:    []string{"UID", "PID", "CMD"},
+		Processes: [][]string{{"root", "4242", "nginx: master process"}},
+	}
+
+	c := newClient(api)
+	defer c.Close()
+	c.procs.root = t.TempDir() // Not on the Docker host
+
+	procs, err := c.GetProcesses(context.Background(), "web")
+	if err != nil {
+		t.Fatalf("GetProcesses() error = %v", err)
+	}
+	if len(procs.Titles) != 3 || len(procs.Processes) != 1 || procs.Processes[0][1] != "4242" || procs.HostStats {
+		t.Errorf("GetProcesses() = %+v; want one nginx process", procs)
+	}
+}
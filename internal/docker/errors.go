@@ -0,0 +1,72 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNoSample means a container's stats stream has not delivered a
+	// sample yet
+	ErrNoSample = errors.New("no stats sample yet")
+
+	// ErrStaleStats means a container's latest stats sample is too old to
+	// describe its current state
+	ErrStaleStats = errors.New("stats are stale")
+
+	// ErrReadOnly means a container action was refused because the client
+	// is read-only
+	ErrReadOnly = errors.New("container actions are disabled in read-only mode")
+)
+
+// ContainerError describes why statistics for one container could not be
+// collected
+type ContainerError struct {
+	ID   string
+	Name string
+	Err  error
+}
+
+// Error implements the error interface
+func (e ContainerError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Name, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e ContainerError) Unwrap() error {
+	return e.Err
+}
+
+// PartialError is returned by GetContainerStats together with the results
+// when statistics could not be collected for some of the containers
+type PartialError struct {
+	Total    int
+	Failures []ContainerError
+}
+
+// Error implements the error interface
+func (e *PartialError) Error() string {
+	reasons := make([]string, len(e.Failures))
+	for i, f := range e.Failures {
+		reasons[i] = f.Error()
+	}
+	return fmt.Sprintf("stats unavailable for %d of %d containers: %s",
+		len(e.Failures), e.Total, strings.Join(reasons, "; "))
+}
+
+// Unwrap returns the per-container errors
+func (e *PartialError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, f := range e.Failures {
+		errs[i] = f
+	}
+	return errs
+}
+
+// IsPartial reports whether err only describes failures of individual
+// containers, in which case the returned statistics are still usable
+func IsPartial(err error) bool {
+	var partial *PartialError
+	return errors.As(err, &partial)
+}
@@ -0,0 +1,100 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/muesli/cancelreader"
+	"golang.org/x/term"
+)
+
+// DefaultShell starts bash when the container has it and sh otherwise
+var DefaultShell = []string{"/bin/sh", "-c", "if command -v bash >/dev/null 2>&1; then exec bash; else exec sh; fi"}
+
+// ExecTerminal runs cmd in a container with a TTY connected to in and out
+// and returns its exit code. When in is a terminal it is switched to raw
+// mode for the duration and the TTY follows its size.
+func (c *Client) ExecTerminal(ctx context.Context, id string, cmd []string, in io.Reader, out io.Writer) (int, error) {
+	if c.ReadOnly() {
+		return 0, ErrReadOnly
+	}
+
+	fd := -1
+	if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		fd = int(f.Fd())
+	}
+
+	opts := container.ExecOptions{
+		Cmd:          cmd,
+		Tty:          true,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+	}
+	if t := os.Getenv("TERM"); t != "" {
+		opts.Env = []string{"TERM=" + t}
+	}
+	if fd >= 0 {
+		if w, h, err := term.GetSize(fd); err == nil {
+			opts.ConsoleSize = &[2]uint{uint(h), uint(w)} // #nosec G115 - terminal sizes are small and non-negative
+		}
+	}
+
+	exec, err := c.cli.ContainerExecCreate(ctx, id, opts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create exec session: %w", err)
+	}
+	conn, err := c.cli.ContainerExecAttach(ctx, exec.ID, container.ExecAttachOptions{Tty: true, ConsoleSize: opts.ConsoleSize})
+	if err != nil {
+		return 0, fmt.Errorf("failed to attach to exec session: %w", err)
+	}
+	defer conn.Close()
+
+	if fd >= 0 {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return 0, fmt.Errorf("failed to set terminal to raw mode: %w", err)
+		}
+		defer term.Restore(fd, state) //nolint:errcheck // best effort on the way out
+
+		resizeCtx, stopResize := context.WithCancel(ctx)
+		defer stopResize()
+		go watchResize(resizeCtx, fd, func(width, height int) {
+			c.cli.ContainerExecResize(resizeCtx, exec.ID, container.ResizeOptions{ //nolint:errcheck // the next resize retries
+				Height: uint(height), // #nosec G115 - terminal sizes are small and non-negative
+				Width:  uint(width),  // #nosec G115 - terminal sizes are small and non-negative
+			})
+		})
+	}
+
+	// The input is read through a cancelable reader, so no keystroke meant
+	// for the caller is swallowed once the session ends
+	input, err := cancelreader.NewReader(in)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read input: %w", err)
+	}
+	inputDone := make(chan struct{})
+	go func() {
+		defer close(inputDone)
+		if _, err := io.Copy(conn.Conn, input); err == nil {
+			conn.CloseWrite() //nolint:errcheck // the session may already be gone
+		}
+	}()
+
+	_, err = io.Copy(out, conn.Reader)
+	input.Cancel()
+	<-inputDone
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, fmt.Errorf("failed to read exec output: %w", err)
+	}
+
+	result, err := c.cli.ContainerExecInspect(ctx, exec.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to inspect exec session: %w", err)
+	}
+	return result.ExitCode, nil
+}
@@ -0,0 +1,57 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExecTerminal(t *testing.T) {
+	api := newFakeAPI()
+	api.execs["web"] = func(conn net.Conn) int {
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		conn.Write([]byte("$ " + strings.TrimSpace(line) + "\r\n"))
+		return 3
+	}
+
+	c := newClient(api)
+	defer c.Close()
+
+	var out bytes.Buffer
+	code, err := c.ExecTerminal(context.Background(), "web", DefaultShell, strings.NewReader("exit 3\n"), &out)
+	if err != nil {
+		t.Fatalf("ExecTerminal() error = %v", err)
+	}
+	if code != 3 {
+		t.Errorf("exit code = %d; want 3", code)
+	}
+	if out.String() != "$ exit 3\r\n" {
+		t.Errorf("output = %q; want the session output", out.String())
+	}
+
+	opts := api.execOpts
+	if !opts.Tty || !opts.AttachStdin || !opts.AttachStdout || !reflect.DeepEqual(opts.Cmd, DefaultShell) {
+		t.Errorf("exec options = %+v; want an attached TTY running the default shell", opts)
+	}
+}
+
+func TestExecTerminalErrors(t *testing.T) {
+	api := newFakeAPI()
+	c := newClient(api)
+	defer c.Close()
+
+	_, err := c.ExecTerminal(context.Background(), "missing", DefaultShell, strings.NewReader(""), &bytes.Buffer{})
+	if err == nil || !strings.HasPrefix(err.Error(), "failed to create exec session") {
+		t.Errorf("ExecTerminal(missing) error = %v; want exec creation error", err)
+	}
+
+	c.SetReadOnly(true)
+	if _, err := c.ExecTerminal(context.Background(), "web", DefaultShell, strings.NewReader(""), &bytes.Buffer{}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("ExecTerminal() in read-only mode error = %v; want ErrReadOnly", err)
+	}
+}
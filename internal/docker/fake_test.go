@@ -0,0 +1,237 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+)
+
+// fakeAPI is an in-memory stand-in for the Docker daemon. Only the methods
+// used by Client are implemented; calling anything else panics through the
+// nil embedded interface.
+type fakeAPI struct {
+	client.APIClient
+
+	mu         sync.Mutex
+	containers []container.Summary
+	stats      map[string]func(w *io.PipeWriter)
+	statsCalls map[string]int
+	listCalls  int
+	listOpts   container.ListOptions // Options of the latest list call
+	listErr    error
+	events     chan events.Message
+
+	inspects     map[string]container.InspectResponse
+	images       map[string]image.InspectResponse
+	inspectCalls int
+	tops         map[string]container.TopResponse
+	logs         map[string][]byte
+	actions      []string
+	execs        map[string]func(conn net.Conn) int // Serves a session, returns the exit code
+	execOpts     container.ExecOptions
+	execCode     int
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{
+		stats:      make(map[string]func(w *io.PipeWriter)),
+		statsCalls: make(map[string]int),
+		events:     make(chan events.Message),
+		inspects:   make(map[string]container.InspectResponse),
+		images:     make(map[string]image.InspectResponse),
+		tops:       make(map[string]container.TopResponse),
+		logs:       make(map[string][]byte),
+		execs:      make(map[string]func(conn net.Conn) int),
+	}
+}
+
+func (f *fakeAPI) Close() error { return nil }
+
+func (f *fakeAPI) ContainerList(_ context.Context, options container.ListOptions) ([]container.Summary, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.listCalls++
+	f.listOpts = options
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+
+	var result []container.Summary
+	for _, cont := range f.containers {
+		if options.Filters.Contains("id") && !options.Filters.ExactMatch("id", cont.ID) {
+			continue
+		}
+		if options.Filters.Contains("status") && !options.Filters.ExactMatch("status", cont.State) {
+			continue
+		}
+		if !options.Filters.MatchKVList("label", cont.Labels) {
+			continue
+		}
+		result = append(result, cont)
+	}
+	return result, nil
+}
+
+func (f *fakeAPI) Events(ctx context.Context, _ events.ListOptions) (<-chan events.Message, <-chan error) {
+	errs := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		errs <- ctx.Err()
+	}()
+	return f.events, errs
+}
+
+func (f *fakeAPI) setContainers(containers ...container.Summary) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.containers = containers
+}
+
+func (f *fakeAPI) lists() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.listCalls
+}
+
+func (f *fakeAPI) ContainerInspect(_ context.Context, id string) (container.InspectResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inspectCalls++
+	info, ok := f.inspects[id]
+	if !ok {
+		return container.InspectResponse{}, errors.New("no such container: " + id)
+	}
+	return info, nil
+}
+
+func (f *fakeAPI) ContainerTop(_ context.Context, id string, _ []string) (container.TopResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	top, ok := f.tops[id]
+	if !ok {
+		return container.TopResponse{}, errors.New("no such container: " + id)
+	}
+	return top, nil
+}
+
+func (f *fakeAPI) ContainerLogs(_ context.Context, id string, _ container.LogsOptions) (io.ReadCloser, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	logs, ok := f.logs[id]
+	if !ok {
+		return nil, errors.New("no such container: " + id)
+	}
+	return io.NopCloser(bytes.NewReader(logs)), nil
+}
+
+func (f *fakeAPI) ContainerStart(_ context.Context, id string, _ container.StartOptions) error {
+	return f.action("start " + id)
+}
+
+func (f *fakeAPI) ContainerStop(_ context.Context, id string, _ container.StopOptions) error {
+	return f.action("stop " + id)
+}
+
+func (f *fakeAPI) ContainerRestart(_ context.Context, id string, _ container.StopOptions) error {
+	return f.action("restart " + id)
+}
+
+func (f *fakeAPI) ContainerPause(_ context.Context, id string) error {
+	return f.action("pause " + id)
+}
+
+func (f *fakeAPI) ContainerUnpause(_ context.Context, id string) error {
+	return f.action("unpause " + id)
+}
+
+func (f *fakeAPI) ContainerKill(_ context.Context, id, signal string) error {
+	return f.action("kill " + id + " " + signal)
+}
+
+// action records a lifecycle call; the container "missing" does not exist
+func (f *fakeAPI) action(call string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if strings.Contains(call, "missing") {
+		return errors.New("no such container: missing")
+	}
+	f.actions = append(f.actions, call)
+	return nil
+}
+
+func (f *fakeAPI) ContainerExecCreate(_ context.Context, id string, options container.ExecOptions) (container.ExecCreateResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.execs[id]; !ok {
+		return container.ExecCreateResponse{}, errors.New("no such container: " + id)
+	}
+	f.execOpts = options
+	return container.ExecCreateResponse{ID: id}, nil
+}
+
+func (f *fakeAPI) ContainerExecAttach(_ context.Context, execID string, _ container.ExecAttachOptions) (types.HijackedResponse, error) {
+	f.mu.Lock()
+	serve := f.execs[execID]
+	f.mu.Unlock()
+
+	client, server := net.Pipe()
+	go func() {
+		code := serve(server)
+		f.mu.Lock()
+		f.execCode = code
+		f.mu.Unlock()
+		server.Close()
+	}()
+	return types.NewHijackedResponse(client, ""), nil
+}
+
+func (f *fakeAPI) ContainerExecInspect(_ context.Context, execID string) (container.ExecInspect, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return container.ExecInspect{ExecID: execID, ExitCode: f.execCode}, nil
+}
+
+func (f *fakeAPI) ImageInspect(_ context.Context, id string, _ ...client.ImageInspectOption) (image.InspectResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inspectCalls++
+	info, ok := f.images[id]
+	if !ok {
+		return image.InspectResponse{}, errors.New("no such image: " + id)
+	}
+	return info, nil
+}
+
+func (f *fakeAPI) ContainerStats(ctx context.Context, id string, _ bool) (container.StatsResponseReader, error) {
+	f.mu.Lock()
+	f.statsCalls[id]++
+	write, ok := f.stats[id]
+	f.mu.Unlock()
+	if !ok {
+		return container.StatsResponseReader{}, errors.New("no such container: " + id)
+	}
+
+	r, w := io.Pipe()
+	go func() {
+		write(w)
+		<-ctx.Done()
+		w.Close()
+	}()
+	return container.StatsResponseReader{Body: r}, nil
+}
+
+func (f *fakeAPI) calls(id string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.statsCalls[id]
+}
@@ -0,0 +1,83 @@
+package docker
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker/api/types/filters"
+)
+
+// FilterKeys are the filter keys accepted by ParseFilter. They are passed
+// to the container list API like docker ps --filter.
+var FilterKeys = []string{"name", "label", "status", "ancestor", "network", "health"}
+
+// Compose labels identifying a container's project and service
+const (
+	composeProjectLabel = "com.docker.compose.project"
+	composeServiceLabel = "com.docker.compose.service"
+)
+
+// Filter restricts the listed containers. Values of the same key match any
+// of them, except labels, which must all match.
+type Filter struct {
+	Key   string
+	Value string
+}
+
+// String returns the filter in key=value form
+func (f Filter) String() string {
+	return f.Key + "=" + f.Value
+}
+
+// FormatFilters returns filters in key=value form separated by commas
+func FormatFilters(list []Filter) string {
+	parts := make([]string, len(list))
+	for i, f := range list {
+		parts[i] = f.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseFilter parses a filter in key=value form
+func ParseFilter(s string) (Filter, error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || value == "" {
+		return Filter{}, fmt.Errorf("invalid filter %q (want key=value)", s)
+	}
+	if !slices.Contains(FilterKeys, key) {
+		return Filter{}, fmt.Errorf("unknown filter key %q (want one of %s)", key, strings.Join(FilterKeys, ", "))
+	}
+	return Filter{Key: key, Value: value}, nil
+}
+
+// ProjectFilter matches the containers of a Docker Compose project
+func ProjectFilter(project string) Filter {
+	return Filter{Key: "label", Value: composeProjectLabel + "=" + project}
+}
+
+// ServiceFilter matches the containers of a Docker Compose service
+func ServiceFilter(service string) Filter {
+	return Filter{Key: "label", Value: composeServiceLabel + "=" + service}
+}
+
+// filterArgs converts filters to list API arguments
+func filterArgs(list []Filter, extra ...filters.KeyValuePair) filters.Args {
+	args := filters.NewArgs(extra...)
+	for _, f := range list {
+		args.Add(f.Key, f.Value)
+	}
+	return args
+}
+
+// SetFilters restricts GetContainerStats to the containers matching all
+// filters. A status filter selects stopped containers without showAll,
+// like docker ps.
+func (c *Client) SetFilters(list []Filter) {
+	c.registry.setFilters(list)
+}
+
+// Filters returns the filters set with SetFilters
+func (c *Client) Filters() []Filter {
+	return c.registry.getFilters()
+}
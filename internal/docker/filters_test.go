@@ -0,0 +1,90 @@
+package docker
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Filter
+		wantErr bool
+	}{
+		{"name=web", Filter{Key: "name", Value: "web"}, false},
+		{"label=com.example.tier=frontend", Filter{Key: "label", Value: "com.example.tier=frontend"}, false},
+		{"health=unhealthy", Filter{Key: "health", Value: "unhealthy"}, false},
+		{"name", Filter{}, true},
+		{"name=", Filter{}, true},
+		{"volume=data", Filter{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFilter(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseFilter(%q) = %+v, %v; want %+v, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	got := FormatFilters([]Filter{ProjectFilter("shop"), ServiceFilter("web")})
+	if got != "label=com.docker.compose.project=shop, label=com.docker.compose.service=web" {
+		t.Errorf("FormatFilters(project, service) = %q", got)
+	}
+}
+
+func TestRegistryFilters(t *testing.T) {
+	shop := map[string]string{composeProjectLabel: "shop", composeServiceLabel: "web"}
+	api := newFakeAPI()
+	api.setContainers(
+		container.Summary{ID: "web", State: "running", Labels: shop},
+		container.Summary{ID: "blog", State: "running", Labels: map[string]string{composeProjectLabel: "blog"}},
+		container.Summary{ID: "old", State: "exited", Labels: shop},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := newContainerRegistry(api)
+	r.setFilters([]Filter{ProjectFilter("shop")})
+	if err := r.ensure(ctx, ctx); err != nil {
+		t.Fatalf("ensure() error = %v", err)
+	}
+	if got := ids(r.list(true)); got != "old,web" {
+		t.Fatalf("list(true) = %s; want the shop containers", got)
+	}
+	drain(r.changes)
+
+	// A container relabelled out of the project is dropped on its event
+	api.setContainers(
+		container.Summary{ID: "web", State: "running", Labels: map[string]string{composeProjectLabel: "blog"}},
+		container.Summary{ID: "old", State: "exited", Labels: shop},
+	)
+	api.events <- events.Message{Type: events.ContainerEventType, Action: events.ActionUpdate, Actor: events.Actor{ID: "web"}}
+	waitForChange(t, r.changes)
+	if got := ids(r.list(true)); got != "old" {
+		t.Errorf("list(true) after relabel = %s; want old", got)
+	}
+
+	// A status filter shows stopped containers without showAll
+	r.setFilters([]Filter{{Key: "status", Value: "exited"}})
+	if err := r.ensure(ctx, ctx); err != nil {
+		t.Fatalf("ensure() error = %v", err)
+	}
+	if got := ids(r.list(false)); got != "old" {
+		t.Errorf("list(false) with status=exited = %s; want old", got)
+	}
+}
+
+// ids returns the sorted IDs of containers joined by commas
+func ids(containers []container.Summary) string {
+	list := make([]string, 0, len(containers))
+	for _, cont := range containers {
+		list = append(list, cont.ID)
+	}
+	sort.Strings(list)
+	return strings.Join(list, ",")
+}
@@ -1,6 +1,9 @@
 package docker
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // FormatBytes formats bytes into human-readable format
 func FormatBytes(bytes uint64) string {
@@ -46,3 +49,27 @@ func FormatBlockIO(read, write uint64) string {
 func FormatMemUsage(usage, limit uint64) string {
 	return fmt.Sprintf("%s / %s", FormatBytes(usage), FormatBytes(limit))
 }
+
+// FormatRate formats a throughput in bytes per second
+func FormatRate(bytesPerSec float64) string {
+	if bytesPerSec < 0 {
+		bytesPerSec = 0
+	}
+	return FormatBytes(uint64(bytesPerSec)) + "/s"
+}
+
+// FormatUptime formats a duration compactly, e.g. 45s, 12m, 5h12m or 3d4h
+func FormatUptime(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "-"
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
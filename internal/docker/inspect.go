@@ -0,0 +1,124 @@
+package docker
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+)
+
+// inspectTTL bounds how long a cached inspect result is trusted when no
+// event invalidated it
+const inspectTTL = 5 * time.Minute
+
+// CacheStats reports how often inspect results were served from the cache
+type CacheStats struct {
+	ContainerHits   uint64
+	ContainerMisses uint64
+	ImageHits       uint64
+	ImageMisses     uint64
+}
+
+// inspectCache caches container inspect results by container ID and image
+// inspect results by image ID. Container entries are invalidated by events,
+// both kinds expire after a TTL. Failed lookups are not cached.
+type inspectCache struct {
+	cli client.APIClient
+	ttl time.Duration
+	now func() time.Time
+
+	mu         sync.Mutex
+	containers map[string]cachedContainer
+	images     map[string]cachedImage
+
+	containerHits   atomic.Uint64
+	containerMisses atomic.Uint64
+	imageHits       atomic.Uint64
+	imageMisses     atomic.Uint64
+}
+
+type cachedContainer struct {
+	info    container.InspectResponse
+	fetched time.Time
+}
+
+type cachedImage struct {
+	info    image.InspectResponse
+	fetched time.Time
+}
+
+// newInspectCache creates an empty cache with the given TTL
+func newInspectCache(cli client.APIClient, ttl time.Duration) *inspectCache {
+	return &inspectCache{
+		cli:        cli,
+		ttl:        ttl,
+		now:        time.Now,
+		containers: make(map[string]cachedContainer),
+		images:     make(map[string]cachedImage),
+	}
+}
+
+// container returns the inspect result for a container
+func (ic *inspectCache) container(ctx context.Context, id string) (container.InspectResponse, error) {
+	ic.mu.Lock()
+	entry, ok := ic.containers[id]
+	ic.mu.Unlock()
+	if ok && ic.now().Sub(entry.fetched) < ic.ttl {
+		ic.containerHits.Add(1)
+		return entry.info, nil
+	}
+
+	ic.containerMisses.Add(1)
+	info, err := ic.cli.ContainerInspect(ctx, id)
+	if err != nil {
+		return container.InspectResponse{}, err
+	}
+
+	ic.mu.Lock()
+	ic.containers[id] = cachedContainer{info: info, fetched: ic.now()}
+	ic.mu.Unlock()
+	return info, nil
+}
+
+// image returns the inspect result for an image
+func (ic *inspectCache) image(ctx context.Context, id string) (image.InspectResponse, error) {
+	ic.mu.Lock()
+	entry, ok := ic.images[id]
+	ic.mu.Unlock()
+	if ok && ic.now().Sub(entry.fetched) < ic.ttl {
+		ic.imageHits.Add(1)
+		return entry.info, nil
+	}
+
+	ic.imageMisses.Add(1)
+	info, err := ic.cli.ImageInspect(ctx, id)
+	if err != nil {
+		return image.InspectResponse{}, err
+	}
+
+	ic.mu.Lock()
+	ic.images[id] = cachedImage{info: info, fetched: ic.now()}
+	ic.mu.Unlock()
+	return info, nil
+}
+
+// invalidate drops the cached inspect result of a container
+func (ic *inspectCache) invalidate(id string) {
+	ic.mu.Lock()
+	delete(ic.containers, id)
+	ic.mu.Unlock()
+}
+
+// stats returns the hit and miss counters
+func (ic *inspectCache) stats() CacheStats {
+	return CacheStats{
+		ContainerHits:   ic.containerHits.Load(),
+		ContainerMisses: ic.containerMisses.Load(),
+		ImageHits:       ic.imageHits.Load(),
+		ImageMisses:     ic.imageMisses.Load(),
+	}
+}
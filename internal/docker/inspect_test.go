@@ -0,0 +1,103 @@
+package docker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/image"
+)
+
+func TestInspectCache(t *testing.T) {
+	api := newFakeAPI()
+	api.inspects["web"] = container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			HostConfig: &container.HostConfig{Resources: container.Resources{NanoCPUs: 1500000000}},
+		},
+	}
+	api.images["sha256:abc"] = image.InspectResponse{Size: 4096}
+
+	now := time.Unix(1000, 0)
+	ic := newInspectCache(api, time.Minute)
+	ic.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := ic.container(ctx, "web"); err != nil {
+			t.Fatalf("container() error = %v", err)
+		}
+		if _, err := ic.image(ctx, "sha256:abc"); err != nil {
+			t.Fatalf("image() error = %v", err)
+		}
+	}
+
+	want := CacheStats{ContainerHits: 2, ContainerMisses: 1, ImageHits: 2, ImageMisses: 1}
+	if got := ic.stats(); got != want {
+		t.Errorf("stats() = %+v; want %+v", got, want)
+	}
+
+	// Invalidation forces a fresh container inspect
+	ic.invalidate("web")
+	ic.container(ctx, "web")
+	if got := ic.stats().ContainerMisses; got != 2 {
+		t.Errorf("ContainerMisses after invalidate = %d; want 2", got)
+	}
+
+	// Expired entries are fetched again
+	now = now.Add(2 * time.Minute)
+	ic.image(ctx, "sha256:abc")
+	if got := ic.stats().ImageMisses; got != 2 {
+		t.Errorf("ImageMisses after TTL = %d; want 2", got)
+	}
+
+	// Failed lookups are not cached
+	ic.container(ctx, "missing")
+	ic.container(ctx, "missing")
+	if got := ic.stats().ContainerMisses; got != 4 {
+		t.Errorf("ContainerMisses after failed lookups = %d; want 4", got)
+	}
+}
+
+func TestRegistryInvalidatesInspectCache(t *testing.T) {
+	api := newFakeAPI()
+	api.setContainers(container.Summary{ID: "web", State: "running"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	invalidated, restarted := make(chan string, 1), make(chan string, 1)
+	r := newContainerRegistry(api)
+	r.invalidate = func(id string) { invalidated <- id }
+	r.restarted = func(id string) { restarted <- id }
+	if err := r.ensure(ctx, ctx); err != nil {
+		t.Fatalf("ensure() error = %v", err)
+	}
+
+	api.events <- events.Message{Type: events.ContainerEventType, Action: events.ActionUpdate, Actor: events.Actor{ID: "web"}}
+	select {
+	case id := <-invalidated:
+		if id != "web" {
+			t.Errorf("invalidated %q; want web", id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("update event did not invalidate the inspect cache")
+	}
+	select {
+	case id := <-restarted:
+		t.Errorf("update event reported %q as restarted", id)
+	default:
+	}
+
+	// Start and stop events also drop per-process state such as MAC addresses
+	api.events <- events.Message{Type: events.ContainerEventType, Action: events.ActionDie, Actor: events.Actor{ID: "web"}}
+	select {
+	case id := <-restarted:
+		if id != "web" {
+			t.Errorf("restarted %q; want web", id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("die event did not report the container as restarted")
+	}
+}
@@ -0,0 +1,173 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// LogSource identifies the output stream a log line was written to
+type LogSource int
+
+const (
+	// Stdout is the container's standard output
+	Stdout LogSource = iota
+	// Stderr is the container's standard error
+	Stderr
+)
+
+// LogLine is one line of container output
+type LogLine struct {
+	Source LogSource
+	Time   time.Time // When the daemon received the line
+	Text   string
+}
+
+// logBuffer bounds how many lines may queue up unread before the reader
+// blocks, which in turn slows down the log stream
+const logBuffer = 1024
+
+// LogFollower delivers the output of a container line by line until it is
+// closed or the container's log stream ends
+type LogFollower struct {
+	lines  chan LogLine
+	cancel context.CancelFunc
+	err    error
+}
+
+// Lines returns the channel of log lines. It is closed when the stream
+// ends, after which Err reports why.
+func (f *LogFollower) Lines() <-chan LogLine {
+	return f.lines
+}
+
+// Err returns the error that ended the stream, or nil if it ended normally
+// or was closed. It must only be called after Lines was closed.
+func (f *LogFollower) Err() error {
+	return f.err
+}
+
+// Close stops following the logs
+func (f *LogFollower) Close() {
+	f.cancel()
+}
+
+// FollowLogs streams the last tail lines of a container's output and then
+// follows new output
+func (c *Client) FollowLogs(ctx context.Context, id string, tail int) (*LogFollower, error) {
+	// Output of containers with a TTY is not multiplexed
+	info, err := c.cli.ContainerInspect(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	tty := info.Config != nil && info.Config.Tty
+
+	ctx, cancel := context.WithCancel(ctx)
+	rc, err := c.cli.ContainerLogs(ctx, id, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: true,
+		Follow:     true,
+		Tail:       strconv.Itoa(tail),
+	})
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to get container logs: %w", err)
+	}
+
+	f := &LogFollower{lines: make(chan LogLine, logBuffer), cancel: cancel}
+	go func() {
+		defer close(f.lines)
+		defer rc.Close() //nolint:errcheck // stream is done
+
+		err := readLogs(ctx, rc, tty, f.lines)
+		if err != nil && ctx.Err() == nil {
+			f.err = err
+		}
+	}()
+	return f, nil
+}
+
+// readLogs splits a log stream into lines and sends them to out
+func readLogs(ctx context.Context, r io.Reader, tty bool, out chan<- LogLine) error {
+	stdout := &lineWriter{ctx: ctx, source: Stdout, out: out}
+	stderr := &lineWriter{ctx: ctx, source: Stderr, out: out}
+
+	var err error
+	if tty {
+		_, err = io.Copy(stdout, r)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, r)
+	}
+	stdout.flush()
+	stderr.flush()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+// lineWriter collects written output into lines. StdCopy writes frames
+// one at a time, so lines from stdout and stderr keep their order.
+type lineWriter struct {
+	ctx     context.Context
+	source  LogSource
+	out     chan<- LogLine
+	partial []byte
+}
+
+// Write implements io.Writer
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := string(w.partial[:i])
+		w.partial = w.partial[i+1:]
+		if err := w.emit(line); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// flush emits a final line without a trailing newline
+func (w *lineWriter) flush() {
+	if len(w.partial) > 0 {
+		w.emit(string(w.partial)) //nolint:errcheck // stream is done
+		w.partial = nil
+	}
+}
+
+// emit sends one line, giving up when the follower was closed
+func (w *lineWriter) emit(raw string) error {
+	line := parseLogLine(w.source, raw)
+	select {
+	case w.out <- line:
+		return nil
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	}
+}
+
+// parseLogLine splits the RFC 3339 timestamp added by the daemon from the
+// text of a line
+func parseLogLine(source LogSource, raw string) LogLine {
+	raw = strings.TrimSuffix(raw, "\r")
+	line := LogLine{Source: source, Text: raw}
+	stamp, text, _ := strings.Cut(raw, " ")
+	if t, err := time.Parse(time.RFC3339Nano, stamp); err == nil {
+		line.Time, line.Text = t, text
+	}
+	return line
+}
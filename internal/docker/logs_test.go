@@ -0,0 +1,78 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+func TestFollowLogs(t *testing.T) {
+	var payload bytes.Buffer
+	stdout := stdcopy.NewStdWriter(&payload, stdcopy.Stdout)
+	stderr := stdcopy.NewStdWriter(&payload, stdcopy.Stderr)
+	stdout.Write([]byte("2024-03-01T12:00:00.000000001Z listening on :80\n2024-03-01T12:00:01Z GET /"))
+	stdout.Write([]byte(" 200\n"))
+	stderr.Write([]byte("2024-03-01T12:00:02Z connection reset\n"))
+	stdout.Write([]byte("2024-03-01T12:00:03Z shutting down"))
+
+	api := newFakeAPI()
+	api.inspects["web"] = container.InspectResponse{Config: &container.Config{}}
+	api.logs["web"] = payload.Bytes()
+
+	c := newClient(api)
+	defer c.Close()
+
+	f, err := c.FollowLogs(context.Background(), "web", 100)
+	if err != nil {
+		t.Fatalf("FollowLogs() error = %v", err)
+	}
+	defer f.Close()
+
+	var lines []LogLine
+	for line := range f.Lines() {
+		lines = append(lines, line)
+	}
+	if err := f.Err(); err != nil {
+		t.Errorf("Err() = %v; want nil", err)
+	}
+
+	want := []LogLine{
+		{Stdout, time.Date(2024, 3, 1, 12, 0, 0, 1, time.UTC), "listening on :80"},
+		{Stdout, time.Date(2024, 3, 1, 12, 0, 1, 0, time.UTC), "GET / 200"},
+		{Stderr, time.Date(2024, 3, 1, 12, 0, 2, 0, time.UTC), "connection reset"},
+		{Stdout, time.Date(2024, 3, 1, 12, 0, 3, 0, time.UTC), "shutting down"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("FollowLogs() delivered %d lines; want %d: %+v", len(lines), len(want), lines)
+	}
+	for i := range want {
+		if lines[i].Source != want[i].Source || !lines[i].Time.Equal(want[i].Time) || lines[i].Text != want[i].Text {
+			t.Errorf("line %d = %+v; want %+v", i, lines[i], want[i])
+		}
+	}
+}
+
+func TestFollowLogsTTY(t *testing.T) {
+	api := newFakeAPI()
+	api.inspects["tty"] = container.InspectResponse{Config: &container.Config{Tty: true}}
+	api.logs["tty"] = []byte("2024-03-01T12:00:00Z prompt\r\nno timestamp\n")
+
+	c := newClient(api)
+	defer c.Close()
+
+	f, err := c.FollowLogs(context.Background(), "tty", 10)
+	if err != nil {
+		t.Fatalf("FollowLogs() error = %v", err)
+	}
+	var lines []LogLine
+	for line := range f.Lines() {
+		lines = append(lines, line)
+	}
+	if len(lines) != 2 || lines[0].Text != "prompt" || lines[1].Text != "no timestamp" || !lines[1].Time.IsZero() {
+		t.Errorf("FollowLogs() = %+v; want prompt and an untimed line", lines)
+	}
+}
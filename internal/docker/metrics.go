@@ -0,0 +1,225 @@
+package docker
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MetricType is the kind of a metric family
+type MetricType int
+
+const (
+	// Gauge is a value that can go up and down
+	Gauge MetricType = iota
+	// Counter is a cumulative value that only resets when the container
+	// restarts
+	Counter
+)
+
+// String returns the Prometheus name of the type
+func (t MetricType) String() string {
+	if t == Counter {
+		return "counter"
+	}
+	return "gauge"
+}
+
+// metricPrefix namespaces all metric names
+const metricPrefix = "docker_stats_"
+
+// Label is a name and value identifying a sample
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is one value of a metric family
+type Sample struct {
+	Labels []Label
+	Value  float64
+}
+
+// Metric is a family of samples with the same name
+type Metric struct {
+	Name    string
+	Help    string
+	Type    MetricType
+	Samples []Sample
+}
+
+// containerMetric defines a per-container metric family. value reports
+// false when the container has no such value.
+type containerMetric struct {
+	name  string
+	help  string
+	typ   MetricType
+	value func(s *ContainerStats) (float64, bool)
+}
+
+// usage only reports a value for containers with current statistics
+func usage(f func(s *ContainerStats) float64) func(s *ContainerStats) (float64, bool) {
+	return func(s *ContainerStats) (float64, bool) {
+		if s.State != "running" || s.Unavailable() || s.Stale {
+			return 0, false
+		}
+		return f(s), true
+	}
+}
+
+// always reports a value for every container
+func always(f func(s *ContainerStats) float64) func(s *ContainerStats) (float64, bool) {
+	return func(s *ContainerStats) (float64, bool) {
+		return f(s), true
+	}
+}
+
+// containerMetrics are the per-container metric families in output order
+var containerMetrics = []containerMetric{
+	{"container_cpu_seconds_total", "CPU time used since the container started", Counter,
+		usage(func(s *ContainerStats) float64 { return s.CPUTime.Seconds() })},
+	{"container_cpu_percent", "CPU usage relative to one host core, or to the CPU limit with -cpu-mode limit", Gauge,
+		usage(func(s *ContainerStats) float64 { return s.CPUPercent })},
+	{"container_cpu_limit_cores", "CPU limit in cores; absent when unlimited", Gauge,
+		func(s *ContainerStats) (float64, bool) { return s.CPULimit, s.CPULimit > 0 }},
+	{"container_cpu_throttled_seconds_total", "Time the container was throttled by its CPU quota", Counter,
+		usage(func(s *ContainerStats) float64 { return s.ThrottledTime.Seconds() })},
+	{"container_memory_usage_bytes", "Memory usage as shown by docker stats, or including the page cache with -raw-memory", Gauge,
+		usage(func(s *ContainerStats) float64 { return float64(s.MemUsage) })},
+	{"container_memory_limit_bytes", "Memory limit, or the host memory when unlimited", Gauge,
+		usage(func(s *ContainerStats) float64 { return float64(s.MemLimit) })},
+	{"container_network_receive_bytes_total", "Bytes received on all interfaces", Counter,
+		usage(func(s *ContainerStats) float64 { return float64(s.NetRx) })},
+	{"container_network_transmit_bytes_total", "Bytes sent on all interfaces", Counter,
+		usage(func(s *ContainerStats) float64 { return float64(s.NetTx) })},
+	{"container_block_read_bytes_total", "Bytes read from block devices", Counter,
+		usage(func(s *ContainerStats) float64 { return float64(s.BlockRead) })},
+	{"container_block_write_bytes_total", "Bytes written to block devices", Counter,
+		usage(func(s *ContainerStats) float64 { return float64(s.BlockWrite) })},
+	{"container_pids", "Number of processes and threads", Gauge,
+		usage(func(s *ContainerStats) float64 { return float64(s.PIDs) })},
+	{"container_pids_limit", "Process limit; absent when unlimited", Gauge,
+		func(s *ContainerStats) (float64, bool) { return float64(s.PIDsLimit), s.PIDsLimit > 0 }},
+	{"container_image_size_bytes", "Size of the container's image", Gauge,
+		always(func(s *ContainerStats) float64 { return float64(s.ImageSize) })},
+	{"container_restarts_total", "Times the daemon restarted the container", Counter,
+		always(func(s *ContainerStats) float64 { return float64(s.RestartCount) })},
+	{"container_oom_kills_total", "OOM kills seen since monitoring started", Counter,
+		always(func(s *ContainerStats) float64 { return float64(s.OOMKills) })},
+}
+
+// containerLabels identifies a container's samples
+func containerLabels(s *ContainerStats) []Label {
+	labels := []Label{{"name", s.Name}, {"id", s.ID}, {"image", s.Image}}
+	if project := s.Labels[composeProjectLabel]; project != "" {
+		labels = append(labels, Label{"compose_project", project})
+	}
+	if service := s.Labels[composeServiceLabel]; service != "" {
+		labels = append(labels, Label{"compose_service", service})
+	}
+	return labels
+}
+
+// withLabel returns labels with one more label appended
+func withLabel(labels []Label, name, value string) []Label {
+	return append(append([]Label(nil), labels...), Label{name, value})
+}
+
+// CollectMetrics converts container statistics and daemon info to metric
+// families. Usage metrics are left out for containers without current
+// statistics rather than reported as zero; info may be nil.
+func CollectMetrics(info *DockerInfo, containers []ContainerStats) []Metric {
+	labels := make([][]Label, len(containers))
+	for i := range containers {
+		labels[i] = containerLabels(&containers[i])
+	}
+
+	state := Metric{Name: metricPrefix + "container_state", Help: "Container state as a label; always 1", Type: Gauge}
+	for i := range containers {
+		state.Samples = append(state.Samples, Sample{withLabel(labels[i], "state", containers[i].State), 1})
+	}
+	metrics := []Metric{state}
+
+	for _, def := range containerMetrics {
+		m := Metric{Name: metricPrefix + def.name, Help: def.help, Type: def.typ}
+		for i := range containers {
+			if v, ok := def.value(&containers[i]); ok {
+				m.Samples = append(m.Samples, Sample{labels[i], v})
+			}
+		}
+		metrics = append(metrics, m)
+	}
+
+	if info != nil {
+		metrics = append(metrics,
+			Metric{Name: metricPrefix + "daemon_info", Help: "Docker daemon version and platform; always 1", Type: Gauge,
+				Samples: []Sample{{[]Label{{"version", info.ServerVersion}, {"os_type", info.OSType}, {"architecture", info.Architecture}}, 1}}},
+			Metric{Name: metricPrefix + "daemon_containers", Help: "Containers known to the daemon by state", Type: Gauge,
+				Samples: []Sample{
+					{[]Label{{"state", "running"}}, float64(info.ContainersRunning)},
+					{[]Label{{"state", "paused"}}, float64(info.ContainersPaused)},
+					{[]Label{{"state", "stopped"}}, float64(info.ContainersStopped)},
+				}},
+			Metric{Name: metricPrefix + "daemon_images", Help: "Images stored by the daemon", Type: Gauge,
+				Samples: []Sample{{nil, float64(info.ImagesTotal)}}},
+			Metric{Name: metricPrefix + "daemon_image_size_bytes", Help: "Total size of all images", Type: Gauge,
+				Samples: []Sample{{nil, float64(info.TotalImageSize)}}},
+			Metric{Name: metricPrefix + "daemon_memory_bytes", Help: "Memory of the daemon's host", Type: Gauge,
+				Samples: []Sample{{nil, float64(info.MemoryTotal)}}},
+			Metric{Name: metricPrefix + "daemon_cpus", Help: "CPUs of the daemon's host", Type: Gauge,
+				Samples: []Sample{{nil, float64(info.CPUs)}}},
+		)
+	}
+	return metrics
+}
+
+// PrometheusContentType is the content type of WritePrometheus output
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// WritePrometheus writes metric families in the Prometheus text exposition
+// format. Families without samples are left out.
+func WritePrometheus(w io.Writer, metrics []Metric) error {
+	bw := bufio.NewWriter(w)
+	for _, m := range metrics {
+		if len(m.Samples) == 0 {
+			continue
+		}
+		fmt.Fprintf(bw, "# HELP %s %s\n", m.Name, escapeHelp(m.Help))
+		fmt.Fprintf(bw, "# TYPE %s %s\n", m.Name, m.Type)
+		for _, s := range m.Samples {
+			bw.WriteString(m.Name) //nolint:errcheck // reported by Flush
+			if len(s.Labels) > 0 {
+				bw.WriteByte('{') //nolint:errcheck // reported by Flush
+				for i, l := range s.Labels {
+					if i > 0 {
+						bw.WriteByte(',') //nolint:errcheck // reported by Flush
+					}
+					fmt.Fprintf(bw, `%s="%s"`, l.Name, escapeLabel(l.Value))
+				}
+				bw.WriteByte('}') //nolint:errcheck // reported by Flush
+			}
+			fmt.Fprintf(bw, " %s\n", strconv.FormatFloat(s.Value, 'g', -1, 64))
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	return nil
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+// escapeHelp escapes a HELP line
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+// escapeLabel escapes a label value
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
@@ -0,0 +1,63 @@
+package docker
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	containers := []ContainerStats{
+		{
+			ID: "0123456789ab", Name: "web", Image: "nginx:alpine", State: "running",
+			Labels:  map[string]string{composeProjectLabel: "shop", composeServiceLabel: "web"},
+			CPUTime: 1500 * time.Millisecond, CPUPercent: 12.5, MemUsage: 1 << 20, MemLimit: 1 << 30,
+			NetRx: 100, NetTx: 200, PIDs: 4, ImageSize: 1000, RestartCount: 2,
+		},
+		// No usage metrics for stopped containers or missing statistics
+		{ID: "fedcba987654", Name: `odd "name"`, Image: "busybox", State: "exited", PIDsLimit: 64},
+		{ID: "aaaaaaaaaaaa", Name: "broken", Image: "busybox", State: "running", Error: "stats unavailable"},
+		{ID: "bbbbbbbbbbbb", Name: "old", Image: "busybox", State: "running", CPUPercent: 50, Error: "stats are stale", Stale: true},
+	}
+	info := &DockerInfo{ServerVersion: "28.5.2", ContainersRunning: 2, ContainersStopped: 1, ImagesTotal: 3, CPUs: 4,
+		OSType: "linux", Architecture: "x86_64"}
+
+	var buf bytes.Buffer
+	if err := WritePrometheus(&buf, CollectMetrics(info, containers)); err != nil {
+		t.Fatalf("WritePrometheus() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"# HELP docker_stats_container_cpu_seconds_total CPU time used since the container started\n" +
+			"# TYPE docker_stats_container_cpu_seconds_total counter\n" +
+			`docker_stats_container_cpu_seconds_total{name="web",id="0123456789ab",image="nginx:alpine",compose_project="shop",compose_service="web"} 1.5` + "\n" +
+			"# HELP",
+		`docker_stats_container_state{name="web",id="0123456789ab",image="nginx:alpine",compose_project="shop",compose_service="web",state="running"} 1`,
+		`docker_stats_container_state{name="odd \"name\"",id="fedcba987654",image="busybox",state="exited"} 1`,
+		`docker_stats_container_memory_limit_bytes{name="web",id="0123456789ab",image="nginx:alpine",compose_project="shop",compose_service="web"} 1.073741824e+09`,
+		`docker_stats_container_pids_limit{name="odd \"name\"",id="fedcba987654",image="busybox"} 64`,
+		`docker_stats_container_restarts_total{name="broken",id="aaaaaaaaaaaa",image="busybox"} 0`,
+		`docker_stats_daemon_info{version="28.5.2",os_type="linux",architecture="x86_64"} 1`,
+		`docker_stats_daemon_containers{state="stopped"} 1`,
+		"docker_stats_daemon_cpus 4\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %q:\n%s", want, out)
+		}
+	}
+
+	if n := strings.Count(out, "docker_stats_container_cpu_percent{"); n != 1 {
+		t.Errorf("cpu_percent has %d samples; want 1 for the only container with statistics", n)
+	}
+	// Families without samples are left out
+	if strings.Contains(out, "cpu_limit_cores") {
+		t.Errorf("metrics contain cpu_limit_cores without limited containers:\n%s", out)
+	}
+
+	buf.Reset()
+	if err := WritePrometheus(&buf, CollectMetrics(nil, nil)); err != nil || strings.Contains(buf.String(), "daemon") {
+		t.Errorf("WritePrometheus(no info) = %q, %v; want no daemon metrics", buf.String(), err)
+	}
+}
@@ -0,0 +1,168 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// InterfaceStats holds the traffic counters of one network interface of a
+// container
+type InterfaceStats struct {
+	Name      string  `json:"name"`              // Interface name inside the container, e.g. eth0
+	Network   string  `json:"network,omitempty"` // Docker network name; empty when it cannot be determined
+	RxBytes   uint64  `json:"rx_bytes"`
+	TxBytes   uint64  `json:"tx_bytes"`
+	RxPackets uint64  `json:"rx_packets"`
+	TxPackets uint64  `json:"tx_packets"`
+	RxErrors  uint64  `json:"rx_errors"`
+	TxErrors  uint64  `json:"tx_errors"`
+	RxDropped uint64  `json:"rx_dropped"`
+	TxDropped uint64  `json:"tx_dropped"`
+	RxRate    float64 `json:"rx_rate"` // Bytes per second received
+	TxRate    float64 `json:"tx_rate"` // Bytes per second sent
+}
+
+// interfaceRates holds the per-second throughput of one interface
+type interfaceRates struct {
+	Rx float64
+	Tx float64
+}
+
+// interfaceStats returns the counters of each interface, sorted by name
+func interfaceStats(stats *StatsJSON, rates map[string]interfaceRates) []InterfaceStats {
+	if len(stats.Networks) == 0 {
+		return nil
+	}
+	ifaces := make([]InterfaceStats, 0, len(stats.Networks))
+	for name, n := range stats.Networks {
+		ifaces = append(ifaces, InterfaceStats{
+			Name:      name,
+			RxBytes:   n.RxBytes,
+			TxBytes:   n.TxBytes,
+			RxPackets: n.RxPackets,
+			TxPackets: n.TxPackets,
+			RxErrors:  n.RxErrors,
+			TxErrors:  n.TxErrors,
+			RxDropped: n.RxDropped,
+			TxDropped: n.TxDropped,
+			RxRate:    rates[name].Rx,
+			TxRate:    rates[name].Tx,
+		})
+	}
+	sort.Slice(ifaces, func(i, j int) bool { return ifaces[i].Name < ifaces[j].Name })
+	return ifaces
+}
+
+// macReader returns the MAC address of an interface inside the network
+// namespace of the process with the given PID
+type macReader func(pid int, iface string) (string, error)
+
+// procMAC reads an interface's MAC address through the container's root
+// filesystem, which only works when running on the Docker host with enough
+// privileges
+func procMAC(pid int, iface string) (string, error) {
+	path := fmt.Sprintf("/proc/%d/root/sys/class/net/%s/address", pid, iface)
+	data, err := os.ReadFile(path) // #nosec G304 - path is built from a PID and an interface name
+	if err != nil {
+		return "", fmt.Errorf("failed to read MAC address of %s: %w", iface, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// macCache remembers the MAC addresses read for each container, so /proc is
+// not read on every refresh. Entries are keyed by the container's PID and
+// dropped when the container starts or stops. Failed reads are cached too,
+// as they fail the same way until the container changes.
+type macCache struct {
+	read macReader
+
+	mu      sync.Mutex
+	entries map[string]cachedMACs
+}
+
+type cachedMACs struct {
+	pid  int
+	macs map[string]macResult
+}
+
+type macResult struct {
+	mac string
+	err error
+}
+
+// newMACCache creates an empty cache reading through read
+func newMACCache(read macReader) *macCache {
+	return &macCache{read: read, entries: make(map[string]cachedMACs)}
+}
+
+// reader returns a macReader for one container that reads through the cache
+func (mc *macCache) reader(id string) macReader {
+	return func(pid int, iface string) (string, error) {
+		mc.mu.Lock()
+		entry, ok := mc.entries[id]
+		if !ok || entry.pid != pid {
+			entry = cachedMACs{pid: pid, macs: make(map[string]macResult)}
+			mc.entries[id] = entry
+		}
+		result, ok := entry.macs[iface]
+		mc.mu.Unlock()
+		if ok {
+			return result.mac, result.err
+		}
+
+		result.mac, result.err = mc.read(pid, iface)
+		mc.mu.Lock()
+		if current, ok := mc.entries[id]; ok && current.pid == pid {
+			current.macs[iface] = result
+		}
+		mc.mu.Unlock()
+		return result.mac, result.err
+	}
+}
+
+// invalidate drops the cached MAC addresses of a container
+func (mc *macCache) invalidate(id string) {
+	mc.mu.Lock()
+	delete(mc.entries, id)
+	mc.mu.Unlock()
+}
+
+// mapNetworks names the Docker network each interface is attached to. The
+// stats API only reports interface names, so a single network is mapped
+// directly and several networks are matched by MAC address when the
+// container's interfaces can be read from the host.
+func mapNetworks(ifaces []InterfaceStats, info container.InspectResponse, readMAC macReader) {
+	if info.NetworkSettings == nil || len(info.NetworkSettings.Networks) == 0 {
+		return
+	}
+	networks := info.NetworkSettings.Networks
+
+	if len(networks) == 1 && len(ifaces) == 1 {
+		for name := range networks {
+			ifaces[0].Network = name
+		}
+		return
+	}
+
+	if info.ContainerJSONBase == nil || info.State == nil || info.State.Pid == 0 || readMAC == nil {
+		return
+	}
+	byMAC := make(map[string]string, len(networks))
+	for name, endpoint := range networks {
+		if endpoint != nil && endpoint.MacAddress != "" {
+			byMAC[strings.ToLower(endpoint.MacAddress)] = name
+		}
+	}
+	for i := range ifaces {
+		mac, err := readMAC(info.State.Pid, ifaces[i].Name)
+		if err != nil {
+			continue
+		}
+		ifaces[i].Network = byMAC[strings.ToLower(mac)]
+	}
+}
@@ -0,0 +1,103 @@
+package docker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+)
+
+func TestInterfaceStatsFixture(t *testing.T) {
+	stats := fixtureStats(t, "stats_cgroup_v1.json", nil)
+	if len(stats.Networks) != 2 {
+		t.Fatalf("Networks has %d interfaces; want 2", len(stats.Networks))
+	}
+
+	eth0, eth1 := stats.Networks[0], stats.Networks[1]
+	if eth0.Name != "eth0" || eth1.Name != "eth1" {
+		t.Fatalf("interfaces = %s, %s; want eth0, eth1", eth0.Name, eth1.Name)
+	}
+	if eth0.RxPackets != 4000 || eth0.TxPackets != 2500 || eth0.RxDropped != 2 {
+		t.Errorf("eth0 = %+v; want 4000 rx packets, 2500 tx packets, 2 dropped", eth0)
+	}
+	if eth1.RxErrors != 1 || eth1.RxDropped != 40 || eth1.TxBytes != 2097152 {
+		t.Errorf("eth1 = %+v; want 1 rx error, 40 dropped, 2097152 tx bytes", eth1)
+	}
+}
+
+func TestMapNetworks(t *testing.T) {
+	inspect := func(pid int, networks map[string]*network.EndpointSettings) container.InspectResponse {
+		return container.InspectResponse{
+			ContainerJSONBase: &container.ContainerJSONBase{State: &container.State{Pid: pid}},
+			NetworkSettings:   &container.NetworkSettings{Networks: networks},
+		}
+	}
+	macs := map[string]string{"eth0": "02:42:AC:11:00:02", "eth1": "02:42:ac:12:00:03"}
+	readMAC := func(_ int, iface string) (string, error) {
+		if mac, ok := macs[iface]; ok {
+			return mac, nil
+		}
+		return "", errors.New("no such interface")
+	}
+
+	t.Run("single network", func(t *testing.T) {
+		ifaces := []InterfaceStats{{Name: "eth0"}}
+		mapNetworks(ifaces, inspect(0, map[string]*network.EndpointSettings{"bridge": {}}), nil)
+		if ifaces[0].Network != "bridge" {
+			t.Errorf("Network = %q; want bridge", ifaces[0].Network)
+		}
+	})
+
+	t.Run("matched by MAC", func(t *testing.T) {
+		ifaces := []InterfaceStats{{Name: "eth0"}, {Name: "eth1"}, {Name: "eth2"}}
+		mapNetworks(ifaces, inspect(42, map[string]*network.EndpointSettings{
+			"frontend": {MacAddress: "02:42:ac:11:00:02"},
+			"overlay":  {MacAddress: "02:42:ac:12:00:03"},
+		}), readMAC)
+		if ifaces[0].Network != "frontend" || ifaces[1].Network != "overlay" || ifaces[2].Network != "" {
+			t.Errorf("networks = %q, %q, %q; want frontend, overlay, empty",
+				ifaces[0].Network, ifaces[1].Network, ifaces[2].Network)
+		}
+	})
+
+	t.Run("interfaces not readable", func(t *testing.T) {
+		ifaces := []InterfaceStats{{Name: "eth0"}, {Name: "eth1"}}
+		mapNetworks(ifaces, inspect(0, map[string]*network.EndpointSettings{"a": {}, "b": {}}), readMAC)
+		if ifaces[0].Network != "" || ifaces[1].Network != "" {
+			t.Errorf("networks = %q, %q; want both empty", ifaces[0].Network, ifaces[1].Network)
+		}
+	})
+}
+
+func TestMACCache(t *testing.T) {
+	reads := 0
+	mc := newMACCache(func(pid int, iface string) (string, error) {
+		reads++
+		if iface == "eth1" {
+			return "", errors.New("no such interface")
+		}
+		return "02:42:ac:11:00:02", nil
+	})
+
+	read := mc.reader("web")
+	for i := 0; i < 2; i++ {
+		if mac, err := read(42, "eth0"); err != nil || mac != "02:42:ac:11:00:02" {
+			t.Fatalf("read(eth0) = %q, %v", mac, err)
+		}
+		if _, err := read(42, "eth1"); err == nil {
+			t.Fatal("read(eth1) error = nil; want the read error")
+		}
+	}
+	if reads != 2 {
+		t.Errorf("read /proc %d times for two interfaces; want 2", reads)
+	}
+
+	// A new PID or a start or stop event reads again
+	read(43, "eth0") //nolint:errcheck // counting reads
+	mc.invalidate("web")
+	read(43, "eth0") //nolint:errcheck // counting reads
+	if reads != 4 {
+		t.Errorf("read /proc %d times after a PID change and an invalidation; want 4", reads)
+	}
+}
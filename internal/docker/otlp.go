@@ -0,0 +1,376 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.38.0"
+	"go.opentelemetry.io/otel/semconv/v1.38.0/containerconv"
+)
+
+const (
+	// otlpTimeout bounds one export request
+	otlpTimeout = 10 * time.Second
+	// otlpMetricsPath is appended to endpoints given without a path, like
+	// OTEL_EXPORTER_OTLP_ENDPOINT
+	otlpMetricsPath = "/v1/metrics"
+	// otlpCumulative is AGGREGATION_TEMPORALITY_CUMULATIVE
+	otlpCumulative = 2
+)
+
+// OTLPExporter pushes container metrics to an OTLP/HTTP endpoint, encoded
+// as JSON so no protobuf or OpenTelemetry SDK is needed. Metric names,
+// units and attributes follow the container semantic conventions.
+type OTLPExporter struct {
+	url     string
+	headers map[string]string
+	scope   otlpScope
+	client  *http.Client
+}
+
+// NewOTLPExporter returns an exporter for an http or https endpoint. The
+// scope names the instrumentation, i.e. this program and its version.
+func NewOTLPExporter(endpoint string, headers map[string]string, scope, version string) (*OTLPExporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q (want http(s)://host:port[/path])", endpoint)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = otlpMetricsPath
+	}
+	return &OTLPExporter{
+		url:     u.String(),
+		headers: headers,
+		scope:   otlpScope{Name: scope, Version: version},
+		client:  &http.Client{Timeout: otlpTimeout},
+	}, nil
+}
+
+// URL returns the URL metrics are posted to
+func (e *OTLPExporter) URL() string {
+	return e.url
+}
+
+// ParseOTLPHeaders parses headers in the comma-separated key=value form of
+// OTEL_EXPORTER_OTLP_HEADERS; values may be URL-encoded
+func ParseOTLPHeaders(s string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid OTLP header %q (want key=value)", pair)
+		}
+		decoded, err := url.QueryUnescape(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid OTLP header %q: %w", key, err)
+		}
+		headers[key] = decoded
+	}
+	return headers, nil
+}
+
+// Export pushes the metrics of the containers with current statistics.
+// info may be nil; it supplies the host attributes.
+func (e *OTLPExporter) Export(ctx context.Context, info *DockerInfo, containers []ContainerStats, now time.Time) error {
+	req := e.request(info, containers, now)
+	if len(req.ResourceMetrics) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", e.scope.Name+"/"+e.scope.Version)
+	for key, value := range e.headers {
+		httpReq.Header.Set(key, value)
+	}
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to push metrics: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // read-only response
+
+	reply, _ := io.ReadAll(io.LimitReader(resp.Body, 4096)) //nolint:errcheck // only used in the error message
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to push metrics: %s: %s", resp.Status, strings.TrimSpace(string(reply)))
+	}
+	return nil
+}
+
+// request builds an export request with one resource per container
+func (e *OTLPExporter) request(info *DockerInfo, containers []ContainerStats, now time.Time) otlpRequest {
+	var host []attribute.KeyValue
+	if info != nil {
+		host = hostAttributes(info)
+	}
+
+	req := otlpRequest{ResourceMetrics: []otlpResourceMetrics{}}
+	for i := range containers {
+		s := &containers[i]
+		// Stale samples would be exported as current values
+		if s.State != "running" || s.Unavailable() || s.Stale {
+			continue
+		}
+		req.ResourceMetrics = append(req.ResourceMetrics, otlpResourceMetrics{
+			Resource: otlpResource{Attributes: otlpAttributes(append(containerAttributes(s), host...))},
+			ScopeMetrics: []otlpScopeMetrics{{
+				Scope:     e.scope,
+				Metrics:   containerOTLPMetrics(s, now),
+				SchemaURL: semconv.SchemaURL,
+			}},
+			SchemaURL: semconv.SchemaURL,
+		})
+	}
+	return req
+}
+
+// containerAttributes returns the resource attributes of a container
+func containerAttributes(s *ContainerStats) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		semconv.ContainerID(s.ID),
+		semconv.ContainerName(s.Name),
+		semconv.ContainerRuntimeName("docker"),
+	}
+	name, tag := splitImage(s.Image)
+	attrs = append(attrs, semconv.ContainerImageName(name))
+	if tag != "" {
+		attrs = append(attrs, semconv.ContainerImageTags(tag))
+	}
+	return attrs
+}
+
+// splitImage splits an image reference into its name and tag, dropping a
+// digest. The colon of a registry port is not a tag separator.
+func splitImage(image string) (name, tag string) {
+	image, _, _ = strings.Cut(image, "@")
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+	return image, ""
+}
+
+// hostArch maps the kernel architectures reported by the daemon to the
+// values of host.arch
+var hostArch = map[string]string{
+	"x86_64":  "amd64",
+	"aarch64": "arm64",
+	"armv7l":  "arm32",
+	"i686":    "x86",
+	"ppc64le": "ppc64",
+	"s390x":   "s390x",
+}
+
+// hostAttributes returns the resource attributes of the daemon's host
+func hostAttributes(info *DockerInfo) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if info.Hostname != "" {
+		attrs = append(attrs, semconv.HostName(info.Hostname))
+	}
+	if arch, ok := hostArch[info.Architecture]; ok {
+		attrs = append(attrs, semconv.HostArchKey.String(arch))
+	}
+	if info.OSType != "" {
+		attrs = append(attrs, semconv.OSTypeKey.String(info.OSType))
+	}
+	return attrs
+}
+
+// containerOTLPMetrics returns the semantic convention metrics of one
+// container. Cumulative values start when the container started.
+func containerOTLPMetrics(s *ContainerStats, now time.Time) []otlpMetric {
+	at := now
+	if !s.SampledAt.IsZero() {
+		at = s.SampledAt
+	}
+	point := func(attrs ...attribute.KeyValue) otlpDataPoint {
+		return otlpDataPoint{Attributes: otlpAttributes(attrs), TimeUnixNano: unixNano(at)}
+	}
+	double := func(p otlpDataPoint, v float64) otlpDataPoint {
+		p.AsDouble = &v
+		return p
+	}
+	integer := func(p otlpDataPoint, v uint64) otlpDataPoint {
+		p.AsInt = strconv.FormatUint(v, 10)
+		return p
+	}
+
+	// Per interface and device when known, else the totals
+	var network []otlpDataPoint
+	for _, n := range s.Networks {
+		iface := semconv.NetworkInterfaceName(n.Name)
+		network = append(network,
+			integer(point(iface, semconv.NetworkIODirectionReceive), n.RxBytes),
+			integer(point(iface, semconv.NetworkIODirectionTransmit), n.TxBytes))
+	}
+	if len(s.Networks) == 0 {
+		network = []otlpDataPoint{
+			integer(point(semconv.NetworkIODirectionReceive), s.NetRx),
+			integer(point(semconv.NetworkIODirectionTransmit), s.NetTx),
+		}
+	}
+	var disk []otlpDataPoint
+	for _, d := range s.Devices {
+		device := semconv.SystemDevice(d.Name)
+		disk = append(disk,
+			integer(point(device, semconv.DiskIODirectionRead), d.ReadBytes),
+			integer(point(device, semconv.DiskIODirectionWrite), d.WriteBytes))
+	}
+	if len(s.Devices) == 0 {
+		disk = []otlpDataPoint{
+			integer(point(semconv.DiskIODirectionRead), s.BlockRead),
+			integer(point(semconv.DiskIODirectionWrite), s.BlockWrite),
+		}
+	}
+
+	// The convention defines CPU usage as an integer gauge of whole CPUs;
+	// container.cpu.time keeps the precise value
+	cpus := uint64(math.Round(max(s.CPUPercentHost, 0) / 100))
+
+	uptime := time.Duration(0)
+	if !s.StartedAt.IsZero() {
+		uptime = max(at.Sub(s.StartedAt), 0)
+	}
+
+	return []otlpMetric{
+		otlpSumMetric(containerconv.CPUTime{}, s.StartedAt, double(point(), s.CPUTime.Seconds())),
+		otlpGaugeMetric(containerconv.CPUUsage{}, integer(point(), cpus)),
+		otlpSumMetric(containerconv.MemoryUsage{}, s.StartedAt, integer(point(), s.MemUsage)),
+		otlpSumMetric(containerconv.NetworkIO{}, s.StartedAt, network...),
+		otlpSumMetric(containerconv.DiskIO{}, s.StartedAt, disk...),
+		otlpGaugeMetric(containerconv.Uptime{}, double(point(), uptime.Seconds())),
+	}
+}
+
+// instrument is the description of a semantic convention metric
+type instrument interface {
+	Name() string
+	Unit() string
+	Description() string
+}
+
+// otlpSumMetric returns a cumulative monotonic sum counting from start;
+// the container conventions define their counters as such
+func otlpSumMetric(inst instrument, start time.Time, points ...otlpDataPoint) otlpMetric {
+	if !start.IsZero() {
+		for i := range points {
+			points[i].StartTimeUnixNano = unixNano(start)
+		}
+	}
+	return otlpMetric{
+		Name: inst.Name(), Unit: inst.Unit(), Description: inst.Description(),
+		Sum: &otlpSum{DataPoints: points, AggregationTemporality: otlpCumulative, IsMonotonic: true},
+	}
+}
+
+// otlpGaugeMetric returns a gauge
+func otlpGaugeMetric(inst instrument, points ...otlpDataPoint) otlpMetric {
+	return otlpMetric{
+		Name: inst.Name(), Unit: inst.Unit(), Description: inst.Description(),
+		Gauge: &otlpGauge{DataPoints: points},
+	}
+}
+
+// unixNano formats a time as the string of nanoseconds OTLP/JSON uses for
+// 64-bit integers
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// otlpAttributes converts attributes to OTLP key-values
+func otlpAttributes(attrs []attribute.KeyValue) []otlpKeyValue {
+	list := make([]otlpKeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		value := otlpAnyValue{}
+		if kv.Value.Type() == attribute.STRINGSLICE {
+			values := []otlpAnyValue{}
+			for _, s := range kv.Value.AsStringSlice() {
+				values = append(values, otlpAnyValue{StringValue: &s})
+			}
+			value.ArrayValue = &otlpArrayValue{Values: values}
+		} else {
+			s := kv.Value.Emit()
+			value.StringValue = &s
+		}
+		list = append(list, otlpKeyValue{Key: string(kv.Key), Value: value})
+	}
+	return list
+}
+
+// OTLP/JSON encoding of ExportMetricsServiceRequest; see
+// opentelemetry/proto/metrics/v1/metrics.proto
+type (
+	otlpRequest struct {
+		ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+	}
+	otlpResourceMetrics struct {
+		Resource     otlpResource       `json:"resource"`
+		ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+		SchemaURL    string             `json:"schemaUrl,omitempty"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeMetrics struct {
+		Scope     otlpScope    `json:"scope"`
+		Metrics   []otlpMetric `json:"metrics"`
+		SchemaURL string       `json:"schemaUrl,omitempty"`
+	}
+	otlpScope struct {
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+	}
+	otlpMetric struct {
+		Name        string     `json:"name"`
+		Unit        string     `json:"unit,omitempty"`
+		Description string     `json:"description,omitempty"`
+		Gauge       *otlpGauge `json:"gauge,omitempty"`
+		Sum         *otlpSum   `json:"sum,omitempty"`
+	}
+	otlpGauge struct {
+		DataPoints []otlpDataPoint `json:"dataPoints"`
+	}
+	otlpSum struct {
+		DataPoints             []otlpDataPoint `json:"dataPoints"`
+		AggregationTemporality int             `json:"aggregationTemporality"`
+		IsMonotonic            bool            `json:"isMonotonic"`
+	}
+	otlpDataPoint struct {
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano,omitempty"`
+		TimeUnixNano      string         `json:"timeUnixNano"`
+		AsDouble          *float64       `json:"asDouble,omitempty"`
+		AsInt             string         `json:"asInt,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string       `json:"key"`
+		Value otlpAnyValue `json:"value"`
+	}
+	otlpAnyValue struct {
+		StringValue *string         `json:"stringValue,omitempty"`
+		ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+	}
+	otlpArrayValue struct {
+		Values []otlpAnyValue `json:"values"`
+	}
+)
@@ -0,0 +1,233 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// collectorStandIn records the requests of an OTLP/HTTP collector
+type collectorStandIn struct {
+	paths   []string
+	headers []http.Header
+	bodies  []map[string]any
+	status  int
+}
+
+func (c *collectorStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	var decoded map[string]any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.paths = append(c.paths, r.URL.Path)
+	c.headers = append(c.headers, r.Header)
+	c.bodies = append(c.bodies, decoded)
+	if c.status != 0 {
+		http.Error(w, "rejected", c.status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte("{}")) //nolint:errcheck // test response
+}
+
+// otlpPath walks decoded JSON by object keys and array indexes
+func otlpPath(t *testing.T, v any, path ...any) any {
+	t.Helper()
+	for _, p := range path {
+		switch p := p.(type) {
+		case string:
+			m, ok := v.(map[string]any)
+			if !ok {
+				t.Fatalf("%v: not an object at %q", path, p)
+			}
+			v = m[p]
+		case int:
+			list, ok := v.([]any)
+			if !ok || p >= len(list) {
+				t.Fatalf("%v: no index %d", path, p)
+			}
+			v = list[p]
+		}
+	}
+	return v
+}
+
+// otlpAttrs flattens OTLP key-values, joining array values with commas
+func otlpAttrs(v any) map[string]string {
+	attrs := map[string]string{}
+	list, _ := v.([]any)
+	for _, kv := range list {
+		kv := kv.(map[string]any)
+		value := kv["value"].(map[string]any)
+		if s, ok := value["stringValue"].(string); ok {
+			attrs[kv["key"].(string)] = s
+			continue
+		}
+		var values []string
+		for _, item := range value["arrayValue"].(map[string]any)["values"].([]any) {
+			values = append(values, item.(map[string]any)["stringValue"].(string))
+		}
+		attrs[kv["key"].(string)] = strings.Join(values, ",")
+	}
+	return attrs
+}
+
+func TestOTLPExport(t *testing.T) {
+	collector := &collectorStandIn{}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	e, err := NewOTLPExporter(server.URL, map[string]string{"Authorization": "Bearer token"}, "docker-stats", "1.0.0")
+	if err != nil {
+		t.Fatalf("NewOTLPExporter() error = %v", err)
+	}
+	if e.URL() != server.URL+"/v1/metrics" {
+		t.Errorf("URL() = %q; want the /v1/metrics path appended", e.URL())
+	}
+
+	started := time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC)
+	now := started.Add(time.Hour)
+	info := &DockerInfo{Hostname: "docker-host", Architecture: "x86_64", OSType: "linux"}
+	containers := []ContainerStats{
+		{
+			ID: "0123456789ab", Name: "web", Image: "registry:5000/shop/web:1.2", State: "running",
+			CPUTime: 90 * time.Second, CPUPercentHost: 150, MemUsage: 64 << 20, NetRx: 4096, NetTx: 2048,
+			Networks:  []InterfaceStats{{Name: "eth0", RxBytes: 4096, TxBytes: 2048}},
+			StartedAt: started,
+		},
+		// No metrics without current statistics
+		{ID: "fedcba987654", Name: "job", Image: "busybox", State: "exited"},
+		{ID: "aaaaaaaaaaaa", Name: "broken", Image: "busybox", State: "running", Error: "stats unavailable"},
+	}
+	if err := e.Export(context.Background(), info, containers, now); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	if len(collector.bodies) != 1 {
+		t.Fatalf("collector received %d requests; want 1", len(collector.bodies))
+	}
+	if collector.paths[0] != "/v1/metrics" || collector.headers[0].Get("Authorization") != "Bearer token" ||
+		collector.headers[0].Get("Content-Type") != "application/json" {
+		t.Errorf("request path %q, headers %v", collector.paths[0], collector.headers[0])
+	}
+
+	body := collector.bodies[0]
+	if n := len(otlpPath(t, body, "resourceMetrics").([]any)); n != 1 {
+		t.Fatalf("resourceMetrics has %d resources; want 1", n)
+	}
+	resource := otlpAttrs(otlpPath(t, body, "resourceMetrics", 0, "resource", "attributes"))
+	for key, want := range map[string]string{
+		"container.id": "0123456789ab", "container.name": "web", "container.runtime.name": "docker",
+		"container.image.name": "registry:5000/shop/web", "container.image.tags": "1.2",
+		"host.name": "docker-host", "host.arch": "amd64", "os.type": "linux",
+	} {
+		if resource[key] != want {
+			t.Errorf("resource %s = %q; want %q", key, resource[key], want)
+		}
+	}
+	if scope := otlpPath(t, body, "resourceMetrics", 0, "scopeMetrics", 0, "scope", "name"); scope != "docker-stats" {
+		t.Errorf("scope name = %v; want docker-stats", scope)
+	}
+
+	metrics := map[string]any{}
+	for _, m := range otlpPath(t, body, "resourceMetrics", 0, "scopeMetrics", 0, "metrics").([]any) {
+		metrics[m.(map[string]any)["name"].(string)] = m
+	}
+	for _, name := range []string{"container.cpu.time", "container.cpu.usage", "container.memory.usage",
+		"container.network.io", "container.disk.io", "container.uptime"} {
+		if metrics[name] == nil {
+			t.Errorf("metric %s missing", name)
+		}
+	}
+
+	cpu := otlpPath(t, metrics["container.cpu.time"], "sum")
+	if otlpPath(t, cpu, "aggregationTemporality") != float64(otlpCumulative) || otlpPath(t, cpu, "isMonotonic") != true {
+		t.Errorf("container.cpu.time is not a cumulative monotonic sum: %v", cpu)
+	}
+	if v := otlpPath(t, cpu, "dataPoints", 0, "asDouble"); v != float64(90) {
+		t.Errorf("container.cpu.time = %v; want 90", v)
+	}
+	if v := otlpPath(t, cpu, "dataPoints", 0, "startTimeUnixNano"); v != unixNano(started) {
+		t.Errorf("container.cpu.time start = %v; want the container start", v)
+	}
+	usage := otlpPath(t, metrics["container.cpu.usage"])
+	if otlpPath(t, usage, "unit") != "{cpu}" || otlpPath(t, usage, "gauge", "dataPoints", 0, "asInt") != "2" {
+		t.Errorf("container.cpu.usage = %v; want an integer gauge of 2 CPUs", usage)
+	}
+	if v := otlpPath(t, metrics["container.memory.usage"], "unit"); v != "By" {
+		t.Errorf("container.memory.usage unit = %v; want By", v)
+	}
+	rx := otlpPath(t, metrics["container.network.io"], "sum", "dataPoints", 0)
+	attrs := otlpAttrs(otlpPath(t, rx, "attributes"))
+	if otlpPath(t, rx, "asInt") != "4096" || attrs["network.io.direction"] != "receive" || attrs["network.interface.name"] != "eth0" {
+		t.Errorf("container.network.io receive point = %v", rx)
+	}
+	if v := otlpPath(t, metrics["container.uptime"], "gauge", "dataPoints", 0, "asDouble"); v != float64(3600) {
+		t.Errorf("container.uptime = %v; want 3600", v)
+	}
+
+	// Rejected exports are errors, and nothing is sent without containers
+	collector.status = http.StatusBadRequest
+	if err := e.Export(context.Background(), nil, containers, now); err == nil || !strings.Contains(err.Error(), "rejected") {
+		t.Errorf("Export() to a rejecting collector error = %v; want the collector's message", err)
+	}
+	if err := e.Export(context.Background(), nil, nil, now); err != nil || len(collector.bodies) != 2 {
+		t.Errorf("Export(no containers) = %v after %d requests; want no request", err, len(collector.bodies))
+	}
+}
+
+func TestNewOTLPExporter(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{"http://localhost:4318", "http://localhost:4318/v1/metrics"},
+		{"https://otel.example.com/", "https://otel.example.com/v1/metrics"},
+		{"http://localhost:4318/custom/metrics", "http://localhost:4318/custom/metrics"},
+		{"localhost:4318", ""},
+		{"ftp://localhost", ""},
+	}
+	for _, tt := range tests {
+		e, err := NewOTLPExporter(tt.endpoint, nil, "docker-stats", "1.0.0")
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("NewOTLPExporter(%q) error = nil; want error", tt.endpoint)
+			}
+			continue
+		}
+		if err != nil || e.URL() != tt.want {
+			t.Errorf("NewOTLPExporter(%q) = %v, %v; want %s", tt.endpoint, e, err, tt.want)
+		}
+	}
+}
+
+func TestParseOTLPHeaders(t *testing.T) {
+	got, err := ParseOTLPHeaders("api-key=secret, Authorization=Basic%20dXNlcg==,")
+	if err != nil || len(got) != 2 || got["api-key"] != "secret" || got["Authorization"] != "Basic dXNlcg==" {
+		t.Errorf("ParseOTLPHeaders() = %v, %v", got, err)
+	}
+	if _, err := ParseOTLPHeaders("novalue"); err == nil {
+		t.Error("ParseOTLPHeaders(novalue) error = nil; want error")
+	}
+}
+
+func TestSplitImage(t *testing.T) {
+	tests := []struct{ image, name, tag string }{
+		{"nginx:alpine", "nginx", "alpine"},
+		{"nginx", "nginx", ""},
+		{"registry:5000/shop/web", "registry:5000/shop/web", ""},
+		{"registry:5000/shop/web:1.2@sha256:abc", "registry:5000/shop/web", "1.2"},
+	}
+	for _, tt := range tests {
+		if name, tag := splitImage(tt.image); name != tt.name || tag != tt.tag {
+			t.Errorf("splitImage(%q) = %q, %q; want %q, %q", tt.image, name, tag, tt.name, tt.tag)
+		}
+	}
+}
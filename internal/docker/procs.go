@@ -0,0 +1,217 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clockTicks is the kernel's USER_HZ, the unit of CPU times in /proc. It is
+// 100 on all mainstream Linux architectures.
+const clockTicks = 100
+
+// procUsage is the CPU and memory usage of one host process
+type procUsage struct {
+	CPUPercent float64 // Since the previous sample; only valid if HasCPU
+	HasCPU     bool
+	RSS        uint64
+}
+
+// procSample is the CPU time of a process at one point in time
+type procSample struct {
+	start uint64 // Start time in ticks since boot, tells reused PIDs apart
+	ticks uint64 // User plus system CPU time
+	at    time.Time
+}
+
+// procSampler reads per-process usage from the host's /proc. CPU
+// percentages are computed between successive calls for a container.
+type procSampler struct {
+	root string
+	now  func() time.Time
+
+	mu      sync.Mutex
+	samples map[string]map[int]procSample // By container ID, then PID
+}
+
+// newProcSampler creates a sampler reading from /proc
+func newProcSampler() *procSampler {
+	return &procSampler{root: "/proc", now: time.Now, samples: make(map[string]map[int]procSample)}
+}
+
+// usage returns the usage of the host PIDs of a container. Processes that
+// cannot be read, or do not belong to the container because the daemon
+// runs on another host or in a VM, are left out.
+func (ps *procSampler) usage(id string, pids []int) map[int]procUsage {
+	now := ps.now()
+	result := make(map[int]procUsage)
+	samples := make(map[int]procSample)
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	previous := ps.samples[id]
+
+	for _, pid := range pids {
+		if !ps.belongsTo(pid, id) {
+			continue
+		}
+		sample, err := ps.readStat(pid)
+		if err != nil {
+			continue
+		}
+		sample.at = now
+		samples[pid] = sample
+
+		u := procUsage{RSS: ps.readRSS(pid)}
+		if prev, ok := previous[pid]; ok && prev.start == sample.start && now.After(prev.at) && sample.ticks >= prev.ticks {
+			cpu := float64(sample.ticks-prev.ticks) / clockTicks
+			u.CPUPercent = cpu / now.Sub(prev.at).Seconds() * 100
+			u.HasCPU = true
+		}
+		result[pid] = u
+	}
+
+	if len(samples) == 0 {
+		delete(ps.samples, id)
+	} else {
+		ps.samples[id] = samples
+	}
+	return result
+}
+
+// belongsTo reports whether a host PID is in the cgroup of a container,
+// whose path contains the container ID with both cgroup drivers
+func (ps *procSampler) belongsTo(pid int, id string) bool {
+	path := filepath.Join(ps.root, strconv.Itoa(pid), "cgroup")
+	data, err := os.ReadFile(path) // #nosec G304 - path is built from a PID
+	return err == nil && id != "" && strings.Contains(string(data), id)
+}
+
+// readStat reads the CPU time and start time from /proc/<pid>/stat
+func (ps *procSampler) readStat(pid int) (procSample, error) {
+	path := filepath.Join(ps.root, strconv.Itoa(pid), "stat")
+	data, err := os.ReadFile(path) // #nosec G304 - path is built from a PID
+	if err != nil {
+		return procSample{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	// The command name may contain spaces; fields are counted after it
+	stat := string(data)
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return procSample{}, fmt.Errorf("malformed %s", path)
+	}
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 20 {
+		return procSample{}, fmt.Errorf("malformed %s", path)
+	}
+
+	// fields[0] is field 3 (state): utime is 14, stime 15, starttime 22
+	utime, err1 := strconv.ParseUint(fields[11], 10, 64)
+	stime, err2 := strconv.ParseUint(fields[12], 10, 64)
+	start, err3 := strconv.ParseUint(fields[19], 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return procSample{}, fmt.Errorf("malformed %s", path)
+	}
+	return procSample{start: start, ticks: utime + stime}, nil
+}
+
+// readRSS reads the resident set size from /proc/<pid>/statm, or 0
+func (ps *procSampler) readRSS(pid int) uint64 {
+	path := filepath.Join(ps.root, strconv.Itoa(pid), "statm")
+	data, err := os.ReadFile(path) // #nosec G304 - path is built from a PID
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return 0
+	}
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return pages * uint64(os.Getpagesize()) // #nosec G115 - the page size is positive
+}
+
+// enrichProcesses adds %CPU and RSS columns in front of the command column
+// and sorts the busiest processes first. It reports whether any process
+// could be read from the host.
+func enrichProcesses(list *ProcessList, id string, ps *procSampler) bool {
+	pidCol := -1
+	for i, title := range list.Titles {
+		if title == "PID" {
+			pidCol = i
+		}
+	}
+	if pidCol < 0 {
+		return false
+	}
+
+	// Rows without a valid PID are kept but not sampled
+	rowPIDs := make(map[int]int, len(list.Processes))
+	pids := make([]int, 0, len(list.Processes))
+	for i, proc := range list.Processes {
+		if pidCol >= len(proc) {
+			continue
+		}
+		pid, err := strconv.Atoi(proc[pidCol])
+		if err != nil || pid <= 0 {
+			continue
+		}
+		rowPIDs[i] = pid
+		pids = append(pids, pid)
+	}
+	usage := ps.usage(id, pids)
+	if len(usage) == 0 {
+		return false
+	}
+	rowUsage := func(i int) (procUsage, bool) {
+		pid, ok := rowPIDs[i]
+		if !ok {
+			return procUsage{}, false
+		}
+		u, ok := usage[pid]
+		return u, ok
+	}
+
+	// Sort first so the PIDs stay aligned with their rows
+	cpu := func(i int) float64 {
+		if u, ok := rowUsage(i); ok && u.HasCPU {
+			return u.CPUPercent
+		}
+		return -1
+	}
+	order := make([]int, len(list.Processes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return cpu(order[a]) > cpu(order[b]) })
+
+	insert := func(values []string, extra ...string) []string {
+		at := max(len(values)-1, 0)
+		return append(append(append([]string(nil), values[:at]...), extra...), values[at:]...)
+	}
+
+	processes := make([][]string, len(order))
+	for row, i := range order {
+		cpuText, rssText := "-", "-"
+		if u, ok := rowUsage(i); ok {
+			if u.HasCPU {
+				cpuText = fmt.Sprintf("%.1f", u.CPUPercent)
+			}
+			if u.RSS > 0 {
+				rssText = FormatBytes(u.RSS)
+			}
+		}
+		processes[row] = insert(list.Processes[i], cpuText, rssText)
+	}
+	list.Titles = insert(list.Titles, "%CPU", "RSS")
+	list.Processes = processes
+	return true
+}
@@ -0,0 +1,114 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// writeProc creates /proc/<pid> files for a process in a container's cgroup
+func writeProc(t *testing.T, root string, pid int, cgroup string, ticks, rssPages uint64) {
+	t.Helper()
+	dir := filepath.Join(root, fmt.Sprint(pid))
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	// utime and stime split the ticks; starttime is field 22
+	stat := fmt.Sprintf("%d (my worker) S 1 1 1 0 -1 4194560 100 0 0 0 %d %d 0 0 20 0 1 0 5000 1000 10\n", pid, ticks/2, ticks-ticks/2)
+	files := map[string]string{
+		"stat":   stat,
+		"statm":  fmt.Sprintf("1000 %d 50 10 0 200 0\n", rssPages),
+		"cgroup": cgroup + "\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGetProcessesHostStats(t *testing.T) {
+	const id = "0123456789abcdef"
+	root := t.TempDir()
+	writeProc(t, root, 100, "0::/system.slice/docker-"+id+".scope", 1000, 256)
+	writeProc(t, root, 200, "0::/system.slice/docker-"+id+".scope", 1000, 512)
+	writeProc(t, root, 300, "0::/user.slice", 1000, 1) // Another process reusing a PID seen by the daemon
+
+	api := newFakeAPI()
+	api.tops[id] = container.TopResponse{
+		Titles: []string{"UID", "PID", "CMD"},
+		Processes: [][]string{
+			{"root", "100", "idle"},
+			{"root", "200", "busy"},
+			{"root", "300", "elsewhere"},
+			{"root", "?", "unparsable"}, // Never sampled as PID 0
+		},
+	}
+	c := newClient(api)
+	defer c.Close()
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	c.procs.root = root
+	c.procs.now = func() time.Time { return now }
+
+	// The first sample has no CPU percentage yet
+	procs, err := c.GetProcesses(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetProcesses() error = %v", err)
+	}
+	want := ProcessList{
+		Titles: []string{"UID", "PID", "%CPU", "RSS", "CMD"},
+		Processes: [][]string{
+			{"root", "100", "-", FormatBytes(256 * uint64(os.Getpagesize())), "idle"},
+			{"root", "200", "-", FormatBytes(512 * uint64(os.Getpagesize())), "busy"},
+			{"root", "300", "-", "-", "elsewhere"},
+			{"root", "?", "-", "-", "unparsable"},
+		},
+		HostStats: true,
+	}
+	if !reflect.DeepEqual(procs, want) {
+		t.Errorf("first GetProcesses() = %+v; want %+v", procs, want)
+	}
+
+	// Two seconds later process 200 used 1.5s of CPU and moves to the top
+	now = now.Add(2 * time.Second)
+	writeProc(t, root, 100, "0::/system.slice/docker-"+id+".scope", 1010, 256)
+	writeProc(t, root, 200, "0::/system.slice/docker-"+id+".scope", 1150, 512)
+	procs, err = c.GetProcesses(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetProcesses() error = %v", err)
+	}
+	var got [][2]string
+	for _, proc := range procs.Processes {
+		got = append(got, [2]string{proc[1], proc[2]})
+	}
+	wantCPU := [][2]string{{"200", "75.0"}, {"100", "5.0"}, {"300", "-"}, {"?", "-"}}
+	if !reflect.DeepEqual(got, wantCPU) {
+		t.Errorf("PID and %%CPU = %v; want %v", got, wantCPU)
+	}
+}
+
+func TestReadStatReusedPID(t *testing.T) {
+	root := t.TempDir()
+	writeProc(t, root, 100, "docker-abc", 500, 1)
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	ps := &procSampler{root: root, now: func() time.Time { return now }, samples: make(map[string]map[int]procSample)}
+	ps.usage("abc", []int{100})
+
+	// Same PID, different start time: no CPU percentage from the old sample
+	stat := "100 (sh) S 1 1 1 0 -1 0 0 0 0 0 10 10 0 0 20 0 1 0 9999 1000 10\n"
+	if err := os.WriteFile(filepath.Join(root, "100", "stat"), []byte(stat), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Second)
+	if u := ps.usage("abc", []int{100})[100]; u.HasCPU {
+		t.Errorf("usage of a reused PID = %+v; want no CPU percentage", u)
+	}
+}
@@ -0,0 +1,72 @@
+package docker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Query narrows the containers shown by the interactive filter. Plain text
+// matches the name, image or ID case-insensitively, ~expr matches the name
+// or image against a regular expression and label=key[=value] matches a
+// label.
+type Query struct {
+	text  string
+	re    *regexp.Regexp
+	label string
+	value *string // nil matches any value of the label
+}
+
+// ParseQuery parses an interactive filter query. The empty query matches
+// every container.
+func ParseQuery(s string) (Query, error) {
+	switch {
+	case strings.HasPrefix(s, "~"):
+		re, err := regexp.Compile(s[1:])
+		if err != nil {
+			return Query{}, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		return Query{re: re}, nil
+	case strings.HasPrefix(s, "label="):
+		key, value, ok := strings.Cut(strings.TrimPrefix(s, "label="), "=")
+		q := Query{label: key}
+		if ok {
+			q.value = &value
+		}
+		return q, nil
+	}
+	return Query{text: strings.ToLower(s)}, nil
+}
+
+// Empty reports whether the query matches every container
+func (q Query) Empty() bool {
+	return q.text == "" && q.re == nil && q.label == "" && q.value == nil
+}
+
+// Match reports whether a container matches the query
+func (q Query) Match(c ContainerStats) bool {
+	switch {
+	case q.re != nil:
+		return q.re.MatchString(c.Name) || q.re.MatchString(c.Image)
+	case q.label != "" || q.value != nil:
+		value, ok := c.Labels[q.label]
+		return ok && (q.value == nil || value == *q.value)
+	}
+	return strings.Contains(strings.ToLower(c.Name), q.text) ||
+		strings.Contains(strings.ToLower(c.Image), q.text) ||
+		strings.HasPrefix(c.ID, q.text)
+}
+
+// FilterContainers returns the containers matching the query
+func FilterContainers(containers []ContainerStats, q Query) []ContainerStats {
+	if q.Empty() {
+		return containers
+	}
+	result := make([]ContainerStats, 0, len(containers))
+	for _, c := range containers {
+		if q.Match(c) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+package docker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFilterContainers(t *testing.T) {
+	containers := []ContainerStats{
+		{ID: "0123456789ab", Name: "shop-web-1", Image: "nginx:alpine", Labels: map[string]string{"tier": "frontend"}},
+		{ID: "abcdef012345", Name: "shop-db-1", Image: "postgres:16", Labels: map[string]string{"tier": "db", "backup": ""}},
+		{ID: "fedcba987654", Name: "blog", Image: "ghost:5", Labels: nil},
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", "shop-web-1,shop-db-1,blog"},
+		{"SHOP", "shop-web-1,shop-db-1"},
+		{"postgres", "shop-db-1"},
+		{"fedc", "blog"},
+		{"~^shop-.*-1$", "shop-web-1,shop-db-1"},
+		{"~gh.st", "blog"},
+		{"label=tier", "shop-web-1,shop-db-1"},
+		{"label=tier=db", "shop-db-1"},
+		{"label=backup=", "shop-db-1"},
+		{"label=", "shop-web-1,shop-db-1,blog"}, // Still typing
+		{"redis", ""},
+	}
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Fatalf("ParseQuery(%q) error = %v", tt.query, err)
+		}
+		var names []string
+		for _, c := range FilterContainers(containers, q) {
+			names = append(names, c.Name)
+		}
+		if got := strings.Join(names, ","); got != tt.want {
+			t.Errorf("FilterContainers(%q) = %s; want %s", tt.query, got, tt.want)
+		}
+	}
+
+	if _, err := ParseQuery("~shop["); err == nil {
+		t.Error("ParseQuery(~shop[) error = nil; want invalid regular expression")
+	}
+}
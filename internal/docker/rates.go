@@ -0,0 +1,82 @@
+package docker
+
+import "time"
+
+// maxRateGap is the longest interval between two samples that is still
+// turned into a rate; older samples would only average out a burst
+const maxRateGap = 30 * time.Second
+
+// ioRates holds per-second throughput between two consecutive samples
+type ioRates struct {
+	NetRx      float64
+	NetTx      float64
+	BlockRead  float64
+	BlockWrite float64
+	ReadIOPS   float64
+	WriteIOPS  float64
+	Interfaces map[string]interfaceRates
+	Devices    map[string]deviceRates
+}
+
+// calculateRates computes throughput from the previous to the current
+// sample of the same stream. Without a usable previous sample all rates
+// are zero.
+func calculateRates(prev, cur *StatsJSON) ioRates {
+	if prev == nil || prev.Read.IsZero() || cur.Read.IsZero() {
+		return ioRates{}
+	}
+	elapsed := cur.Read.Sub(prev.Read)
+	if elapsed <= 0 || elapsed > maxRateGap {
+		return ioRates{}
+	}
+	seconds := elapsed.Seconds()
+
+	prevRx, prevTx := netTotals(prev)
+	curRx, curTx := netTotals(cur)
+	prevRead, prevWrite := blockTotals(prev)
+	curRead, curWrite := blockTotals(cur)
+	prevReadOps, prevWriteOps := blockOps(prev)
+	curReadOps, curWriteOps := blockOps(cur)
+
+	rates := ioRates{
+		NetRx:      counterRate(prevRx, curRx, seconds),
+		NetTx:      counterRate(prevTx, curTx, seconds),
+		BlockRead:  counterRate(prevRead, curRead, seconds),
+		BlockWrite: counterRate(prevWrite, curWrite, seconds),
+		ReadIOPS:   counterRate(prevReadOps, curReadOps, seconds),
+		WriteIOPS:  counterRate(prevWriteOps, curWriteOps, seconds),
+		Interfaces: make(map[string]interfaceRates, len(cur.Networks)),
+		Devices:    make(map[string]deviceRates),
+	}
+	for name, n := range cur.Networks {
+		// Interfaces that just appeared have no rate yet
+		if p, ok := prev.Networks[name]; ok {
+			rates.Interfaces[name] = interfaceRates{
+				Rx: counterRate(p.RxBytes, n.RxBytes, seconds),
+				Tx: counterRate(p.TxBytes, n.TxBytes, seconds),
+			}
+		}
+	}
+	prevDevices := deviceTotals(prev)
+	for key, d := range deviceTotals(cur) {
+		if p, ok := prevDevices[key]; ok {
+			rates.Devices[key] = deviceRates{
+				Read:      counterRate(p.readBytes, d.readBytes, seconds),
+				Write:     counterRate(p.writeBytes, d.writeBytes, seconds),
+				ReadIOPS:  counterRate(p.readOps, d.readOps, seconds),
+				WriteIOPS: counterRate(p.writeOps, d.writeOps, seconds),
+			}
+		}
+	}
+	return rates
+}
+
+// counterRate returns the per-second increase of a cumulative counter. A
+// counter that went backwards was reset (e.g. an interface was recreated),
+// which yields zero rather than a negative or wrapped-around value.
+func counterRate(prev, cur uint64, seconds float64) float64 {
+	if cur < prev || seconds <= 0 {
+		return 0
+	}
+	return float64(cur-prev) / seconds
+}
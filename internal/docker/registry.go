@@ -0,0 +1,348 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+)
+
+const (
+	// resyncInterval is how often the registry relists all containers as a
+	// safety net against missed events
+	resyncInterval = 30 * time.Second
+
+	// eventsRetryDelay is how long the registry waits before resubscribing
+	// after the events stream failed
+	eventsRetryDelay = 2 * time.Second
+)
+
+// watchedActions are the container events that change the container set,
+// the listed state of a container or its inspect data
+var watchedActions = []events.Action{
+	events.ActionCreate,
+	events.ActionStart,
+	events.ActionDie,
+	events.ActionDestroy,
+	events.ActionRename,
+	events.ActionPause,
+	events.ActionUnPause,
+	events.ActionOOM,
+	events.ActionHealthStatus,
+	events.ActionUpdate,
+}
+
+// containerRegistry is an in-memory view of the daemon's containers, kept
+// up to date incrementally from the events API
+type containerRegistry struct {
+	cli client.APIClient
+
+	mu         sync.RWMutex
+	containers map[string]container.Summary
+	ooms       map[string]uint64
+	started    bool
+	synced     bool
+	filters    []Filter // Applied by the daemon to every listing
+
+	// changes has a single consumer; a signal is received by only one
+	// reader
+	changes chan struct{}
+
+	// invalidate, if set, is called with the ID of every container an
+	// event was received for
+	invalidate func(id string)
+
+	// restarted, if set, is called with the ID of every container that
+	// started, died or was destroyed
+	restarted func(id string)
+}
+
+// newContainerRegistry creates an empty registry
+func newContainerRegistry(cli client.APIClient) *containerRegistry {
+	return &containerRegistry{
+		cli:        cli,
+		containers: make(map[string]container.Summary),
+		ooms:       make(map[string]uint64),
+		changes:    make(chan struct{}, 1),
+	}
+}
+
+// ensure starts watching events and performs the initial full listing.
+// Events keep running under bg, the listing uses ctx. A background update
+// that failed since the last call forces a full listing; only an error of
+// that listing is returned.
+func (r *containerRegistry) ensure(ctx, bg context.Context) error {
+	r.mu.Lock()
+	if !r.started {
+		r.started = true
+		go r.run(bg)
+	}
+	synced := r.synced
+	r.mu.Unlock()
+
+	if !synced {
+		return r.resync(ctx)
+	}
+	return nil
+}
+
+// fail handles a failed background update. The registry may have missed
+// a change, so the next ensure relists everything.
+func (r *containerRegistry) fail(ctx context.Context, err error) {
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+	r.mu.Lock()
+	r.synced = false
+	r.mu.Unlock()
+
+	r.notify()
+}
+
+// setFilters replaces the list filters; the next ensure relists
+func (r *containerRegistry) setFilters(list []Filter) {
+	r.mu.Lock()
+	r.filters = append([]Filter(nil), list...)
+	r.synced = false
+	r.mu.Unlock()
+}
+
+// getFilters returns the list filters
+func (r *containerRegistry) getFilters() []Filter {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]Filter(nil), r.filters...)
+}
+
+// list returns the known containers; stopped ones only when showAll is set
+// or a status filter chose them
+func (r *containerRegistry) list(showAll bool) []container.Summary {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, f := range r.filters {
+		if f.Key == "status" {
+			showAll = true
+		}
+	}
+	result := make([]container.Summary, 0, len(r.containers))
+	for _, cont := range r.containers {
+		if showAll || cont.State == "running" || cont.State == "paused" {
+			result = append(result, cont)
+		}
+	}
+	return result
+}
+
+// resync replaces the registry contents with a full container listing
+func (r *containerRegistry) resync(ctx context.Context) error {
+	containers, err := r.cli.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Size:    true,
+		Filters: filterArgs(r.getFilters()),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	fresh := make(map[string]container.Summary, len(containers))
+	for _, cont := range containers {
+		fresh[cont.ID] = cont
+	}
+
+	r.mu.Lock()
+	changed := !sameContainerSet(r.containers, fresh)
+	r.containers = fresh
+	r.synced = true
+	// Containers removed while the events stream was down get no destroy
+	for id := range r.ooms {
+		if _, ok := fresh[id]; !ok {
+			delete(r.ooms, id)
+		}
+	}
+	r.mu.Unlock()
+
+	if changed {
+		r.notify()
+	}
+	return nil
+}
+
+// refresh relists a single container, dropping it if it no longer exists
+// or no longer matches the filters. Computing sizes is expensive for the
+// daemon, so they are kept from the last full listing.
+func (r *containerRegistry) refresh(ctx context.Context, id string) error {
+	containers, err := r.cli.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filterArgs(r.getFilters(), filters.Arg("id", id)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list container %s: %w", id, err)
+	}
+
+	r.mu.Lock()
+	old, known := r.containers[id]
+	delete(r.containers, id)
+	for _, cont := range containers {
+		if cont.ID == id {
+			if known {
+				cont.SizeRw, cont.SizeRootFs = old.SizeRw, old.SizeRootFs
+			}
+			r.containers[id] = cont
+		}
+	}
+	cont, found := r.containers[id]
+	r.mu.Unlock()
+
+	if known != found || (found && !sameListing(old, cont)) {
+		r.notify()
+	}
+	return nil
+}
+
+// oomKills returns how many OOM events were seen for a container
+func (r *containerRegistry) oomKills(id string) uint64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.ooms[id]
+}
+
+// remove drops a container from the registry
+func (r *containerRegistry) remove(id string) {
+	r.mu.Lock()
+	_, known := r.containers[id]
+	delete(r.containers, id)
+	delete(r.ooms, id)
+	r.mu.Unlock()
+
+	if known {
+		r.notify()
+	}
+}
+
+// notify signals a change without blocking; pending signals are coalesced
+func (r *containerRegistry) notify() {
+	select {
+	case r.changes <- struct{}{}:
+	default:
+	}
+}
+
+// run follows the events stream until ctx is done, resubscribing and
+// resyncing whenever the stream fails
+func (r *containerRegistry) run(ctx context.Context) {
+	args := filters.NewArgs(filters.Arg("type", string(events.ContainerEventType)))
+	for _, action := range watchedActions {
+		args.Add("event", string(action))
+	}
+
+	resync := time.NewTicker(resyncInterval)
+	defer resync.Stop()
+
+	for {
+		streamCtx, cancel := context.WithCancel(ctx)
+		msgs, errs := r.cli.Events(streamCtx, events.ListOptions{Filters: args})
+
+		r.follow(ctx, msgs, errs, resync.C)
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventsRetryDelay):
+		}
+
+		// Events may have been missed while the stream was down
+		r.fail(ctx, r.resync(ctx))
+	}
+}
+
+// follow handles events until the stream fails or ctx is done
+func (r *containerRegistry) follow(ctx context.Context, msgs <-chan events.Message, errs <-chan error, resync <-chan time.Time) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-errs:
+			return
+		case <-resync:
+			r.fail(ctx, r.resync(ctx))
+		case msg, ok := <-msgs:
+			if !ok {
+				return
+			}
+			r.handle(ctx, msg)
+		}
+	}
+}
+
+// handle applies a single container event to the registry
+func (r *containerRegistry) handle(ctx context.Context, msg events.Message) {
+	id := msg.Actor.ID
+	if id == "" {
+		return
+	}
+
+	if !strings.HasPrefix(string(msg.Action), string(events.ActionHealthStatus)) && !isWatchedAction(msg.Action) {
+		return
+	}
+	if r.invalidate != nil {
+		r.invalidate(id)
+	}
+	switch msg.Action {
+	case events.ActionStart, events.ActionDie, events.ActionDestroy:
+		if r.restarted != nil {
+			r.restarted(id)
+		}
+	}
+
+	switch msg.Action {
+	case events.ActionDestroy:
+		r.remove(id)
+		return
+	case events.ActionOOM:
+		r.mu.Lock()
+		r.ooms[id]++
+		r.mu.Unlock()
+		r.notify()
+	}
+	r.fail(ctx, r.refresh(ctx, id))
+}
+
+// sameContainerSet reports whether two listings hold the same containers
+// in the same states
+func sameContainerSet(a, b map[string]container.Summary) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id, cont := range a {
+		other, ok := b[id]
+		if !ok || other.State != cont.State {
+			return false
+		}
+	}
+	return true
+}
+
+// sameListing reports whether two listings of a container show the same
+// state, status, names and image
+func sameListing(a, b container.Summary) bool {
+	return a.State == b.State && a.Status == b.Status && a.Image == b.Image && slices.Equal(a.Names, b.Names)
+}
+
+// isWatchedAction reports whether an event action affects the registry
+func isWatchedAction(action events.Action) bool {
+	for _, a := range watchedActions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
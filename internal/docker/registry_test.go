@@ -0,0 +1,266 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+)
+
+func TestRegistryFollowsEvents(t *testing.T) {
+	api := newFakeAPI()
+	api.setContainers(container.Summary{ID: "web", State: "running"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := newContainerRegistry(api)
+	if err := r.ensure(ctx, ctx); err != nil {
+		t.Fatalf("ensure() error = %v", err)
+	}
+	if got := len(r.list(false)); got != 1 {
+		t.Fatalf("list() returned %d containers; want 1", got)
+	}
+	drain(r.changes)
+
+	// A new container shows up as soon as its start event arrives
+	api.setContainers(
+		container.Summary{ID: "web", State: "running"},
+		container.Summary{ID: "db", State: "running"},
+	)
+	api.events <- events.Message{Type: events.ContainerEventType, Action: events.ActionStart, Actor: events.Actor{ID: "db"}}
+	waitForChange(t, r.changes)
+	if got := len(r.list(false)); got != 2 {
+		t.Fatalf("list() after start returned %d containers; want 2", got)
+	}
+
+	// A dead container is kept only when listing all containers
+	api.setContainers(
+		container.Summary{ID: "web", State: "exited"},
+		container.Summary{ID: "db", State: "running"},
+	)
+	api.events <- events.Message{Type: events.ContainerEventType, Action: events.ActionDie, Actor: events.Actor{ID: "web"}}
+	waitForChange(t, r.changes)
+	if got := len(r.list(false)); got != 1 {
+		t.Errorf("list(false) after die returned %d containers; want 1", got)
+	}
+	if got := len(r.list(true)); got != 2 {
+		t.Errorf("list(true) after die returned %d containers; want 2", got)
+	}
+
+	// Destroy removes the container without relisting
+	lists := api.lists()
+	api.events <- events.Message{Type: events.ContainerEventType, Action: events.ActionDestroy, Actor: events.Actor{ID: "web"}}
+	waitForChange(t, r.changes)
+	if got := len(r.list(true)); got != 1 {
+		t.Errorf("list(true) after destroy returned %d containers; want 1", got)
+	}
+	if api.lists() != lists {
+		t.Error("destroy event triggered a container list")
+	}
+}
+
+func TestRegistryHealthStatusEvent(t *testing.T) {
+	api := newFakeAPI()
+	api.setContainers(container.Summary{ID: "web", State: "running", Status: "Up 1 minute (starting)"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := newContainerRegistry(api)
+	if err := r.ensure(ctx, ctx); err != nil {
+		t.Fatalf("ensure() error = %v", err)
+	}
+	drain(r.changes)
+
+	api.setContainers(container.Summary{ID: "web", State: "running", Status: "Up 1 minute (healthy)"})
+	api.events <- events.Message{Type: events.ContainerEventType, Action: events.ActionHealthStatusHealthy, Actor: events.Actor{ID: "web"}}
+	waitForChange(t, r.changes)
+	if got := r.list(false)[0].Status; got != "Up 1 minute (healthy)" {
+		t.Errorf("Status after health_status event = %q; want healthy", got)
+	}
+}
+
+func TestRegistryCountsOOMEvents(t *testing.T) {
+	api := newFakeAPI()
+	api.setContainers(container.Summary{ID: "web", State: "running"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := newContainerRegistry(api)
+	if err := r.ensure(ctx, ctx); err != nil {
+		t.Fatalf("ensure() error = %v", err)
+	}
+	drain(r.changes)
+
+	for i := 0; i < 2; i++ {
+		api.events <- events.Message{Type: events.ContainerEventType, Action: events.ActionOOM, Actor: events.Actor{ID: "web"}}
+		waitForChange(t, r.changes)
+	}
+	if got := r.oomKills("web"); got != 2 {
+		t.Errorf("oomKills() = %d; want 2", got)
+	}
+
+	api.events <- events.Message{Type: events.ContainerEventType, Action: events.ActionDestroy, Actor: events.Actor{ID: "web"}}
+	waitForChange(t, r.changes)
+	if got := r.oomKills("web"); got != 0 {
+		t.Errorf("oomKills() after destroy = %d; want 0", got)
+	}
+}
+
+func TestRegistryRelistsAfterFailedRefresh(t *testing.T) {
+	api := newFakeAPI()
+	api.setContainers(container.Summary{ID: "web", State: "running"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := newContainerRegistry(api)
+	if err := r.ensure(ctx, ctx); err != nil {
+		t.Fatalf("ensure() error = %v", err)
+	}
+	drain(r.changes)
+
+	// A failed refresh may leave a stale entry, so the next ensure relists
+	api.mu.Lock()
+	api.listErr = errors.New("daemon unavailable")
+	api.mu.Unlock()
+	api.events <- events.Message{Type: events.ContainerEventType, Action: events.ActionDie, Actor: events.Actor{ID: "web"}}
+	waitForChange(t, r.changes)
+
+	// The relist still failing is an error
+	if err := r.ensure(ctx, ctx); err == nil || !strings.Contains(err.Error(), "daemon unavailable") {
+		t.Errorf("ensure() with a failing relist error = %v; want the list error", err)
+	}
+
+	// A successful relist replaces the stale entry and clears the failure
+	api.mu.Lock()
+	api.listErr = nil
+	api.mu.Unlock()
+	api.setContainers(container.Summary{ID: "web", State: "exited"})
+	lists := api.lists()
+	if err := r.ensure(ctx, ctx); err != nil {
+		t.Errorf("ensure() after a successful relist error = %v; want nil", err)
+	}
+	if api.lists() != lists+1 || len(r.list(false)) != 0 {
+		t.Errorf("ensure() after a failed refresh did not relist: %d lists, %d running", api.lists()-lists, len(r.list(false)))
+	}
+}
+
+func TestRegistryRefreshKeepsSizes(t *testing.T) {
+	api := newFakeAPI()
+	api.setContainers(
+		container.Summary{ID: "web", State: "running", SizeRw: 100, SizeRootFs: 1000},
+		container.Summary{ID: "db", State: "running"},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := newContainerRegistry(api)
+	if err := r.ensure(ctx, ctx); err != nil {
+		t.Fatalf("ensure() error = %v", err)
+	}
+	drain(r.changes)
+	api.events <- events.Message{Type: events.ContainerEventType, Action: events.ActionOOM, Actor: events.Actor{ID: "db"}}
+	waitForChange(t, r.changes)
+
+	// Events relist without sizes, which the daemon computes expensively
+	api.setContainers(container.Summary{ID: "web", State: "running", Status: "Up 1 minute (healthy)"},
+		container.Summary{ID: "db", State: "running"})
+	api.events <- events.Message{Type: events.ContainerEventType, Action: events.ActionHealthStatusHealthy, Actor: events.Actor{ID: "web"}}
+	waitForChange(t, r.changes)
+	api.mu.Lock()
+	size := api.listOpts.Size
+	api.mu.Unlock()
+	if size {
+		t.Error("event refresh requested container sizes")
+	}
+	for _, cont := range r.list(false) {
+		if cont.ID == "web" && (cont.SizeRw != 100 || cont.SizeRootFs != 1000) {
+			t.Errorf("sizes after refresh = %d, %d; want the ones of the full listing", cont.SizeRw, cont.SizeRootFs)
+		}
+	}
+
+	// A resync drops OOM counts of containers removed while events were missed
+	api.setContainers(container.Summary{ID: "web", State: "running"})
+	if err := r.resync(ctx); err != nil {
+		t.Fatalf("resync() error = %v", err)
+	}
+	r.mu.RLock()
+	_, leaked := r.ooms["db"]
+	r.mu.RUnlock()
+	if leaked {
+		t.Error("resync kept the OOM count of a removed container")
+	}
+}
+
+func TestRegistryNotifiesOnlyChanges(t *testing.T) {
+	api := newFakeAPI()
+	api.setContainers(container.Summary{ID: "web", State: "running", Status: "Up 1 minute"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := newContainerRegistry(api)
+	if err := r.resync(ctx); err != nil {
+		t.Fatalf("resync() error = %v", err)
+	}
+	drain(r.changes)
+
+	// Relisting an unchanged container or removing an unknown one is silent
+	if err := r.refresh(ctx, "web"); err != nil {
+		t.Fatalf("refresh() error = %v", err)
+	}
+	r.remove("unknown")
+	select {
+	case <-r.changes:
+		t.Error("unchanged container signalled a change")
+	default:
+	}
+
+	api.setContainers(container.Summary{ID: "web", State: "running", Status: "Up 1 minute (Paused)"})
+	if err := r.refresh(ctx, "web"); err != nil {
+		t.Fatalf("refresh() error = %v", err)
+	}
+	select {
+	case <-r.changes:
+	default:
+		t.Error("changed status did not signal a change")
+	}
+}
+
+func TestSameContainerSet(t *testing.T) {
+	a := map[string]container.Summary{"x": {ID: "x", State: "running"}}
+	b := map[string]container.Summary{"x": {ID: "x", State: "exited"}}
+	if !sameContainerSet(a, a) {
+		t.Error("sameContainerSet(a, a) = false; want true")
+	}
+	if sameContainerSet(a, b) {
+		t.Error("sameContainerSet() with different states = true; want false")
+	}
+	if sameContainerSet(a, map[string]container.Summary{}) {
+		t.Error("sameContainerSet() with different sizes = true; want false")
+	}
+}
+
+func drain(ch <-chan struct{}) {
+	select {
+	case <-ch:
+	default:
+	}
+}
+
+func waitForChange(t *testing.T, ch <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a registry change")
+	}
+}
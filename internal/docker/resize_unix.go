@@ -0,0 +1,31 @@
+//go:build !windows
+
+package docker
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/term"
+)
+
+// watchResize calls resize with the size of the terminal on fd whenever
+// the terminal is resized, until ctx is done
+func watchResize(ctx context.Context, fd int, resize func(width, height int)) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGWINCH)
+	defer signal.Stop(sigs)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigs:
+			if w, h, err := term.GetSize(fd); err == nil {
+				resize(w, h)
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+//go:build windows
+
+package docker
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// resizePoll is how often the console size is checked; Windows has no
+// resize signal
+const resizePoll = 250 * time.Millisecond
+
+// watchResize calls resize with the size of the console on fd whenever it
+// changes, until ctx is done
+func watchResize(ctx context.Context, fd int, resize func(width, height int)) {
+	ticker := time.NewTicker(resizePoll)
+	defer ticker.Stop()
+
+	// Start from a zero size if the console can't be read yet, so the first
+	// successful read is sent
+	width, height, err := term.GetSize(fd)
+	if err != nil {
+		width, height = 0, 0
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w, h, err := term.GetSize(fd)
+			if err == nil && (w != width || h != height) {
+				width, height = w, h
+				resize(w, h)
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package docker
+
+import "time"
+
+// SnapshotVersion is the version of the Snapshot JSON document. It is only
+// increased on incompatible changes; fields may be added at any time.
+const SnapshotVersion = 1
+
+// Snapshot is a machine-readable document of the statistics at one point
+// in time. Sizes are in bytes, rates in bytes or operations per second and
+// percentages are plain numbers, so 150.5 means 150.5%.
+type Snapshot struct {
+	Version    int              `json:"version"`
+	Time       time.Time        `json:"time"`
+	CPUMode    string           `json:"cpu_mode"`   // What cpu_percent is relative to: host or limit
+	RawMemory  bool             `json:"raw_memory"` // mem_usage includes the page cache
+	Docker     *DockerInfo      `json:"docker"`     // null when the daemon info is unavailable
+	Containers []ContainerStats `json:"containers"`
+}
+
+// NewSnapshot builds a snapshot of containers and daemon info taken at now
+func (c *Client) NewSnapshot(info *DockerInfo, containers []ContainerStats, now time.Time) Snapshot {
+	if containers == nil {
+		containers = []ContainerStats{}
+	}
+	return Snapshot{
+		Version:    SnapshotVersion,
+		Time:       now,
+		CPUMode:    c.CPUMode().String(),
+		RawMemory:  c.rawMemory.Load(),
+		Docker:     info,
+		Containers: containers,
+	}
+}
@@ -0,0 +1,54 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestSnapshotJSON pins the JSON document; scripts depend on its field
+// names, so changing them means a new SnapshotVersion
+func TestSnapshotJSON(t *testing.T) {
+	c := newClient(newFakeAPI())
+	defer c.Close()
+	c.SetCPUMode(CPUModeLimit)
+
+	started := time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	info := &DockerInfo{ServerVersion: "28.5.2", ContainersTotal: 2, ContainersRunning: 1, ContainersStopped: 1,
+		ImagesTotal: 3, TotalImageSize: 1 << 30, MemoryTotal: 8 << 30, CPUs: 4, OSType: "linux", Architecture: "x86_64", Hostname: "docker-host"}
+	containers := []ContainerStats{
+		{
+			ID: "0123456789ab", Name: "web", Image: "nginx:alpine", Status: "Up 1 hour", State: "running",
+			Labels:     map[string]string{"com.docker.compose.project": "shop"},
+			CPUPercent: 75, CPUPercentHost: 150, CPULimit: 2, OnlineCPUs: 4, PerCPUPercent: []float64{100, 50},
+			CPUTime: 90 * time.Second, ThrottledTime: 1500 * time.Millisecond, MemUsage: 64 << 20, MemUsageRaw: 80 << 20, MemLimit: 512 << 20, MemPercent: 12.5,
+			NetRx: 4096, NetTx: 2048, NetRxRate: 512.5,
+			Networks: []InterfaceStats{{Name: "eth0", Network: "shop_default", RxBytes: 4096, TxBytes: 2048, RxRate: 512.5}},
+			Devices:  []DeviceStats{{Major: 8, Minor: 0, Name: "sda", ReadBytes: 1 << 20, ReadOps: 16}},
+			PIDs:     12, PIDsLimit: 100, ImageSize: 50 << 20, Created: started.Add(-time.Minute),
+			Health: "healthy", RestartCount: 1, RestartPolicy: "unless-stopped", StartedAt: started, SampledAt: now,
+		},
+		{ID: "fedcba987654", Name: "job", Image: "busybox", Status: "Exited (1) 5 minutes ago", State: "exited", ExitCode: 1, Created: started},
+	}
+
+	got, err := json.MarshalIndent(c.NewSnapshot(info, containers, now), "", "  ")
+	if err != nil {
+		t.Fatalf("json.MarshalIndent() error = %v", err)
+	}
+	want, err := os.ReadFile("testdata/snapshot_v1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(append(got, '\n'), want) {
+		t.Errorf("snapshot JSON differs from testdata/snapshot_v1.json:\n%s", got)
+	}
+
+	// Without containers the list is empty, not null
+	empty, _ := json.Marshal(c.NewSnapshot(nil, nil, now))
+	if !bytes.Contains(empty, []byte(`"docker":null,"containers":[]`)) {
+		t.Errorf("empty snapshot = %s; want null docker info and no containers", empty)
+	}
+}
@@ -0,0 +1,237 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Streaming output formats
+const (
+	StreamNDJSON = "ndjson"
+	StreamCSV    = "csv"
+)
+
+// Column is a scalar value of a streamed record. Names match the JSON
+// field names of ContainerStats.
+type Column struct {
+	Name  string
+	value func(now time.Time, s *ContainerStats) any
+}
+
+// Value returns the column's value for a container sampled at now. Zero
+// timestamps are nil.
+func (c Column) Value(now time.Time, s *ContainerStats) any {
+	return c.value(now, s)
+}
+
+// timeValue returns t, or nil when it is zero
+func timeValue(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+// columns are all streamable columns in the order -columns all uses
+var columns = []Column{
+	{"time", func(now time.Time, _ *ContainerStats) any { return now }},
+	{"id", func(_ time.Time, s *ContainerStats) any { return s.ID }},
+	{"name", func(_ time.Time, s *ContainerStats) any { return s.Name }},
+	{"image", func(_ time.Time, s *ContainerStats) any { return s.Image }},
+	{"status", func(_ time.Time, s *ContainerStats) any { return s.Status }},
+	{"state", func(_ time.Time, s *ContainerStats) any { return s.State }},
+	{"cpu_percent", func(_ time.Time, s *ContainerStats) any { return s.CPUPercent }},
+	{"cpu_percent_host", func(_ time.Time, s *ContainerStats) any { return s.CPUPercentHost }},
+	{"cpu_limit", func(_ time.Time, s *ContainerStats) any { return s.CPULimit }},
+	{"online_cpus", func(_ time.Time, s *ContainerStats) any { return s.OnlineCPUs }},
+	{"cpuset", func(_ time.Time, s *ContainerStats) any { return s.CPUSet }},
+	{"cpu_time_ns", func(_ time.Time, s *ContainerStats) any { return int64(s.CPUTime) }},
+	{"throttled_percent", func(_ time.Time, s *ContainerStats) any { return s.ThrottledPercent }},
+	{"throttled_periods", func(_ time.Time, s *ContainerStats) any { return s.ThrottledPeriods }},
+	{"throttled_time_ns", func(_ time.Time, s *ContainerStats) any { return int64(s.ThrottledTime) }},
+	{"mem_usage", func(_ time.Time, s *ContainerStats) any { return s.MemUsage }},
+	{"mem_usage_raw", func(_ time.Time, s *ContainerStats) any { return s.MemUsageRaw }},
+	{"mem_limit", func(_ time.Time, s *ContainerStats) any { return s.MemLimit }},
+	{"mem_percent", func(_ time.Time, s *ContainerStats) any { return s.MemPercent }},
+	{"mem_anon", func(_ time.Time, s *ContainerStats) any { return s.MemAnon }},
+	{"mem_file", func(_ time.Time, s *ContainerStats) any { return s.MemFile }},
+	{"mem_shmem", func(_ time.Time, s *ContainerStats) any { return s.MemShmem }},
+	{"mem_swap", func(_ time.Time, s *ContainerStats) any { return s.MemSwap }},
+	{"mem_max_usage", func(_ time.Time, s *ContainerStats) any { return s.MemMaxUsage }},
+	{"mem_failcnt", func(_ time.Time, s *ContainerStats) any { return s.MemFailcnt }},
+	{"oom_kills", func(_ time.Time, s *ContainerStats) any { return s.OOMKills }},
+	{"net_rx", func(_ time.Time, s *ContainerStats) any { return s.NetRx }},
+	{"net_tx", func(_ time.Time, s *ContainerStats) any { return s.NetTx }},
+	{"block_read", func(_ time.Time, s *ContainerStats) any { return s.BlockRead }},
+	{"block_write", func(_ time.Time, s *ContainerStats) any { return s.BlockWrite }},
+	{"net_rx_rate", func(_ time.Time, s *ContainerStats) any { return s.NetRxRate }},
+	{"net_tx_rate", func(_ time.Time, s *ContainerStats) any { return s.NetTxRate }},
+	{"block_read_rate", func(_ time.Time, s *ContainerStats) any { return s.BlockReadRate }},
+	{"block_write_rate", func(_ time.Time, s *ContainerStats) any { return s.BlockWriteRate }},
+	{"block_read_iops", func(_ time.Time, s *ContainerStats) any { return s.BlockReadIOPS }},
+	{"block_write_iops", func(_ time.Time, s *ContainerStats) any { return s.BlockWriteIOPS }},
+	{"pids", func(_ time.Time, s *ContainerStats) any { return s.PIDs }},
+	{"pids_limit", func(_ time.Time, s *ContainerStats) any { return s.PIDsLimit }},
+	{"image_size", func(_ time.Time, s *ContainerStats) any { return s.ImageSize }},
+	{"container_size", func(_ time.Time, s *ContainerStats) any { return s.ContainerSize }},
+	{"created", func(_ time.Time, s *ContainerStats) any { return timeValue(s.Created) }},
+	{"health", func(_ time.Time, s *ContainerStats) any { return s.Health }},
+	{"health_failing_streak", func(_ time.Time, s *ContainerStats) any { return s.HealthFailingStreak }},
+	{"restart_count", func(_ time.Time, s *ContainerStats) any { return s.RestartCount }},
+	{"restart_policy", func(_ time.Time, s *ContainerStats) any { return s.RestartPolicy }},
+	{"oom_killed", func(_ time.Time, s *ContainerStats) any { return s.OOMKilled }},
+	{"exit_code", func(_ time.Time, s *ContainerStats) any { return s.ExitCode }},
+	{"started_at", func(_ time.Time, s *ContainerStats) any { return timeValue(s.StartedAt) }},
+	{"finished_at", func(_ time.Time, s *ContainerStats) any { return timeValue(s.FinishedAt) }},
+	{"error", func(_ time.Time, s *ContainerStats) any { return s.Error }},
+	{"stale", func(_ time.Time, s *ContainerStats) any { return s.Stale }},
+	{"sampled_at", func(_ time.Time, s *ContainerStats) any { return timeValue(s.SampledAt) }},
+}
+
+// DefaultColumns are the columns streamed without -columns
+const DefaultColumns = "time,id,name,state,cpu_percent,mem_usage,mem_limit,mem_percent," +
+	"net_rx_rate,net_tx_rate,block_read_rate,block_write_rate,pids,error"
+
+// ColumnNames returns the names of all streamable columns
+func ColumnNames() []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// ParseColumns parses a comma-separated list of column names, or "all"
+func ParseColumns(spec string) ([]Column, error) {
+	if strings.TrimSpace(spec) == "all" {
+		return columns, nil
+	}
+	var list []Column
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, c := range columns {
+			if c.Name == name {
+				list, found = append(list, c), true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("no columns selected")
+	}
+	return list, nil
+}
+
+// StreamWriter writes one record per container and interval
+type StreamWriter interface {
+	// Write writes the records of one interval and flushes them
+	Write(now time.Time, containers []ContainerStats) error
+}
+
+// NewStreamWriter returns a writer of the given streaming format or Go
+// template. Templates ignore cols.
+func NewStreamWriter(w io.Writer, format string, cols []Column) (StreamWriter, error) {
+	if IsTemplate(format) {
+		t, err := newTemplateWriter(w, format)
+		if err != nil {
+			return nil, err
+		}
+		return t, nil
+	}
+	switch format {
+	case StreamNDJSON:
+		return &ndjsonWriter{w: w, columns: cols}, nil
+	case StreamCSV:
+		return &csvWriter{w: csv.NewWriter(w), columns: cols}, nil
+	}
+	return nil, fmt.Errorf("unknown format %q (want %s, %s or a Go template)", format, StreamNDJSON, StreamCSV)
+}
+
+// ndjsonWriter writes one JSON object per line with the keys in column
+// order
+type ndjsonWriter struct {
+	w       io.Writer
+	columns []Column
+}
+
+// Write implements StreamWriter
+func (n *ndjsonWriter) Write(now time.Time, containers []ContainerStats) error {
+	var buf bytes.Buffer
+	for i := range containers {
+		buf.WriteByte('{')
+		for j, col := range n.columns {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			value, err := json.Marshal(col.Value(now, &containers[i]))
+			if err != nil {
+				return fmt.Errorf("failed to encode %s: %w", col.Name, err)
+			}
+			fmt.Fprintf(&buf, "%q:%s", col.Name, value)
+		}
+		buf.WriteString("}\n")
+	}
+	if _, err := n.w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write records: %w", err)
+	}
+	return nil
+}
+
+// csvWriter writes a header row followed by one row per record
+type csvWriter struct {
+	w       *csv.Writer
+	columns []Column
+	header  bool
+}
+
+// Write implements StreamWriter
+func (c *csvWriter) Write(now time.Time, containers []ContainerStats) error {
+	row := make([]string, len(c.columns))
+	if !c.header {
+		for i, col := range c.columns {
+			row[i] = col.Name
+		}
+		c.w.Write(row) //nolint:errcheck // reported by Error after Flush
+		c.header = true
+	}
+	for i := range containers {
+		for j, col := range c.columns {
+			row[j] = csvValue(col.Value(now, &containers[i]))
+		}
+		c.w.Write(row) //nolint:errcheck // reported by Error after Flush
+	}
+	c.w.Flush()
+	if err := c.w.Error(); err != nil {
+		return fmt.Errorf("failed to write records: %w", err)
+	}
+	return nil
+}
+
+// csvValue formats a column value for CSV: raw numbers, RFC 3339
+// timestamps and an empty field for nil
+func csvValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(v)
+}
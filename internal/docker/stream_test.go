@@ -0,0 +1,107 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseColumns(t *testing.T) {
+	cols, err := ParseColumns(DefaultColumns)
+	if err != nil {
+		t.Fatalf("ParseColumns(DefaultColumns) error = %v", err)
+	}
+	if cols[0].Name != "time" || cols[len(cols)-1].Name != "error" {
+		t.Errorf("ParseColumns(DefaultColumns) = %s..%s; want time..error", cols[0].Name, cols[len(cols)-1].Name)
+	}
+
+	all, err := ParseColumns("all")
+	if err != nil || len(all) != len(ColumnNames()) {
+		t.Errorf("ParseColumns(all) = %d columns, %v; want %d", len(all), err, len(ColumnNames()))
+	}
+
+	cols, err = ParseColumns(" name , cpu_percent,")
+	if err != nil || len(cols) != 2 || cols[0].Name != "name" || cols[1].Name != "cpu_percent" {
+		t.Errorf("ParseColumns(name, cpu_percent) = %v, %v", cols, err)
+	}
+
+	for _, spec := range []string{"name,cpu", "", " , "} {
+		if _, err := ParseColumns(spec); err == nil {
+			t.Errorf("ParseColumns(%q) error = nil; want error", spec)
+		}
+	}
+}
+
+// TestColumnNames checks that column names match the JSON field names of
+// ContainerStats, so streamed records and snapshots agree
+func TestColumnNames(t *testing.T) {
+	data, err := json.Marshal(ContainerStats{StartedAt: time.Now(), FinishedAt: time.Now(), SampledAt: time.Now(),
+		Health: "healthy", RestartPolicy: "always", CPUSet: "0-1", Error: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range ColumnNames() {
+		if _, ok := fields[name]; !ok && name != "time" {
+			t.Errorf("column %q is not a ContainerStats JSON field", name)
+		}
+	}
+}
+
+func TestStreamWriter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	containers := []ContainerStats{
+		{ID: "0123456789ab", Name: "web", CPUPercent: 12.5, MemUsage: 1 << 20, SampledAt: now},
+		{ID: "fedcba987654", Name: "db, primary", Error: "stats unavailable"},
+	}
+	cols, err := ParseColumns("time,name,cpu_percent,mem_usage,sampled_at,error")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	w, err := NewStreamWriter(&buf, StreamNDJSON, cols)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(now, containers); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want := `{"time":"2024-03-01T12:00:00Z","name":"web","cpu_percent":12.5,"mem_usage":1048576,"sampled_at":"2024-03-01T12:00:00Z","error":""}
+{"time":"2024-03-01T12:00:00Z","name":"db, primary","cpu_percent":0,"mem_usage":0,"sampled_at":null,"error":"stats unavailable"}
+`
+	if buf.String() != want {
+		t.Errorf("NDJSON =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	w, err = NewStreamWriter(&buf, StreamCSV, cols)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The header is only written before the first interval
+	for range 2 {
+		if err := w.Write(now, containers[:1]); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Write(now, containers[1:]); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want = `time,name,cpu_percent,mem_usage,sampled_at,error
+2024-03-01T12:00:00Z,web,12.5,1048576,2024-03-01T12:00:00Z,
+2024-03-01T12:00:00Z,web,12.5,1048576,2024-03-01T12:00:00Z,
+2024-03-01T12:00:00Z,"db, primary",0,0,,stats unavailable
+`
+	if buf.String() != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	if _, err := NewStreamWriter(&buf, "xml", cols); err == nil {
+		t.Error("NewStreamWriter(xml) error = nil; want error")
+	}
+}
@@ -0,0 +1,172 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+// tablePrefix makes a template format aligned with a header row, like
+// docker stats --format "table {{.Name}}\t{{.CPUPercent}}"
+const tablePrefix = "table "
+
+// templateFuncs are the helper functions available to templates
+var templateFuncs = template.FuncMap{
+	"bytes":    func(v any) string { return FormatBytesInt64(toInt64(v)) },
+	"percent":  FormatPercent,
+	"rate":     FormatRate,
+	"netio":    FormatNetIO,
+	"blockio":  FormatBlockIO,
+	"memusage": FormatMemUsage,
+	"uptime":   FormatUptime,
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"join":     strings.Join,
+	"truncate": truncateRunes,
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// toInt64 converts the integer types of ContainerStats for bytes
+func toInt64(v any) int64 {
+	switch v := v.(type) {
+	case uint64:
+		return int64(min(v, 1<<63-1)) // #nosec G115 - clamped to the int64 range
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+// truncateRunes shortens s to n runes, ending it with … when cut
+func truncateRunes(n int, s string) string {
+	r := []rune(s)
+	if len(r) <= n || n < 1 {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// IsTemplate reports whether a -format value is a Go template rather than
+// a named format
+func IsTemplate(format string) bool {
+	return strings.Contains(format, "{{")
+}
+
+// templateWriter renders each container with a Go template
+type templateWriter struct {
+	w      io.Writer
+	tmpl   *template.Template
+	table  bool
+	header []string
+}
+
+// newTemplateWriter parses a template format. The escapes \t and \n may
+// be written literally, as shells make real tabs awkward.
+func newTemplateWriter(w io.Writer, format string) (*templateWriter, error) {
+	text, table := strings.CutPrefix(format, tablePrefix)
+	text = strings.NewReplacer(`\t`, "\t", `\n`, "\n").Replace(text)
+
+	tmpl, err := template.New("format").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid format template: %w", err)
+	}
+	// Catch unknown fields now rather than on the first interval
+	if err := tmpl.Execute(io.Discard, ContainerStats{}); err != nil {
+		return nil, fmt.Errorf("invalid format template: %w", err)
+	}
+
+	t := &templateWriter{w: w, tmpl: tmpl, table: table}
+	if table {
+		t.header = templateHeader(text)
+	}
+	return t, nil
+}
+
+// Write implements StreamWriter. Tables are aligned per interval and start
+// with the header row.
+func (t *templateWriter) Write(_ time.Time, containers []ContainerStats) error {
+	out := t.w
+	var tw *tabwriter.Writer
+	if t.table {
+		tw = tabwriter.NewWriter(t.w, 10, 1, 3, ' ', 0)
+		out = tw
+		fmt.Fprintln(tw, strings.Join(t.header, "\t"))
+	}
+
+	var buf bytes.Buffer
+	for _, c := range containers {
+		buf.Reset()
+		if err := t.tmpl.Execute(&buf, c); err != nil {
+			return fmt.Errorf("failed to execute format template: %w", err)
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		if _, err := out.Write(buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to write records: %w", err)
+		}
+	}
+
+	if tw != nil {
+		if err := tw.Flush(); err != nil {
+			return fmt.Errorf("failed to write records: %w", err)
+		}
+	}
+	return nil
+}
+
+var (
+	templateAction = regexp.MustCompile(`\{\{(.*?)\}\}`)
+	templateField  = regexp.MustCompile(`\.([A-Z][A-Za-z0-9]*)`)
+)
+
+// templateHeader names each tab-separated column of a table template after
+// the first field it uses, e.g. CPU PERCENT for {{percent .CPUPercent}}
+func templateHeader(text string) []string {
+	cells := strings.Split(strings.TrimRight(text, "\n"), "\t")
+	header := make([]string, len(cells))
+	for i, cell := range cells {
+		for _, action := range templateAction.FindAllStringSubmatch(cell, -1) {
+			if field := templateField.FindStringSubmatch(action[1]); field != nil {
+				header[i] = headerName(field[1])
+				break
+			}
+		}
+	}
+	return header
+}
+
+// headerName splits a field name into upper case words: CPUPercent becomes
+// CPU PERCENT and PIDsLimit PIDS LIMIT
+func headerName(field string) string {
+	r := []rune(field)
+	var b strings.Builder
+	for i, c := range r {
+		if i > 0 && unicode.IsUpper(c) {
+			// A plural s stays with its acronym, as in PIDs
+			nextLower := i+1 < len(r) && unicode.IsLower(r[i+1]) &&
+				(r[i+1] != 's' || i+2 < len(r) && unicode.IsLower(r[i+2]))
+			if unicode.IsLower(r[i-1]) || nextLower {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(c))
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package docker
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestHeaderName(t *testing.T) {
+	tests := map[string]string{
+		"Name":          "NAME",
+		"ID":            "ID",
+		"CPUPercent":    "CPU PERCENT",
+		"NetRxRate":     "NET RX RATE",
+		"PIDs":          "PIDS",
+		"PIDsLimit":     "PIDS LIMIT",
+		"OnlineCPUs":    "ONLINE CPUS",
+		"CPUSet":        "CPU SET",
+		"BlockReadIOPS": "BLOCK READ IOPS",
+	}
+	for field, want := range tests {
+		if got := headerName(field); got != want {
+			t.Errorf("headerName(%q) = %q; want %q", field, got, want)
+		}
+	}
+}
+
+func TestTemplateWriter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	containers := []ContainerStats{
+		{ID: "0123456789ab", Name: "web", CPUPercent: 12.5, MemUsage: 64 << 20, MemLimit: 1 << 30, NetRx: 2048, NetTx: 1024, PIDs: 4},
+		{ID: "fedcba987654", Name: "database", CPUPercent: 150, MemUsage: 1 << 30, ImageSize: 2 << 20, PIDs: 32},
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{
+			"plain",
+			`{{.Name}}: {{percent .CPUPercent}} {{bytes .MemUsage}} {{bytes .ImageSize}}`,
+			"web: 12.50% 64.0MiB 0B\ndatabase: 150.00% 1.0GiB 2.0MiB\n",
+		},
+		{
+			"table",
+			`table {{.Name}}\t{{percent .CPUPercent}}\t{{memusage .MemUsage .MemLimit}}\t{{.PIDs}}`,
+			"NAME       CPU PERCENT   MEM USAGE          PIDS\n" +
+				"web        12.50%        64.0MiB / 1.0GiB   4\n" +
+				"database   150.00%       1.0GiB / 0B        32\n",
+		},
+		{
+			"helpers",
+			`{{upper (truncate 4 .Name)}} {{netio .NetRx .NetTx}} {{json .ID}}`,
+			"WEB 2.0KiB / 1.0KiB \"0123456789ab\"\nDAT… 0B / 0B \"fedcba987654\"\n",
+		},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		w, err := NewStreamWriter(&buf, tt.format, nil)
+		if err != nil {
+			t.Fatalf("%s: NewStreamWriter() error = %v", tt.name, err)
+		}
+		if err := w.Write(now, containers); err != nil {
+			t.Fatalf("%s: Write() error = %v", tt.name, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%s: output =\n%q\nwant\n%q", tt.name, buf.String(), tt.want)
+		}
+	}
+
+	// Unknown fields and syntax errors are reported before any output
+	for _, format := range []string{"{{.CPUPerc}}", "{{.Name", "{{nope .Name}}"} {
+		if _, err := NewStreamWriter(&bytes.Buffer{}, format, nil); err == nil {
+			t.Errorf("NewStreamWriter(%q) error = nil; want error", format)
+		}
+	}
+}
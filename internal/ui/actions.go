@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/tradik/cv-xslt/scripts/tools/stats/internal/docker"
+)
+
+// actionTimeout bounds a container action; stopping waits for the grace
+// period before the daemon kills the container
+const actionTimeout = 30 * time.Second
+
+// confirmOpen reports whether an action awaits confirmation
+func (a *App) confirmOpen() bool {
+	name, _ := a.pages.GetFrontPage()
+	return name == "confirm"
+}
+
+// requestAction asks for confirmation of the action bound to key on the
+// selected container
+func (a *App) requestAction(key rune) {
+	row, _ := a.table.GetSelection()
+
+	a.mu.RLock()
+	if row < 1 || row > len(a.containers) {
+		a.mu.RUnlock()
+		return
+	}
+	c := a.containers[row-1]
+	a.mu.RUnlock()
+
+	if a.client.ReadOnly() {
+		a.setOutcome("[red]" + docker.ErrReadOnly.Error())
+		return
+	}
+
+	var action docker.Action
+	switch key {
+	case 'S':
+		action = docker.StartStopAction(c.State)
+	case 'P':
+		action = docker.PauseAction(c.State)
+	case 'T':
+		action = docker.ActionRestart
+	case 'K':
+		action = docker.ActionKill
+	}
+
+	// Kill offers one button per signal
+	verb := strings.ToUpper(action.String()[:1]) + action.String()[1:]
+	text := fmt.Sprintf("%s container %s?", verb, c.Name)
+	buttons := []string{verb, "Cancel"}
+	if action == docker.ActionKill {
+		text = fmt.Sprintf("Kill container %s with signal:", c.Name)
+		buttons = append(append([]string(nil), docker.KillSignals...), "Cancel")
+	}
+
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons(buttons).
+		SetDoneFunc(func(index int, label string) {
+			a.pages.RemovePage("confirm")
+			a.app.SetFocus(a.table)
+			if index < 0 || label == "Cancel" {
+				return
+			}
+			signal := ""
+			if action == docker.ActionKill {
+				signal = label
+			}
+			go a.runAction(action, c.ID, c.Name, signal)
+		})
+	a.pages.AddPage("confirm", modal, false, true)
+	a.app.SetFocus(modal)
+}
+
+// runAction performs a confirmed action and shows its outcome
+func (a *App) runAction(action docker.Action, id, name, signal string) {
+	a.app.QueueUpdateDraw(func() {
+		a.setOutcome(fmt.Sprintf("[gray]%s %s...", action, name))
+	})
+
+	ctx, cancel := context.WithTimeout(a.ctx, actionTimeout)
+	defer cancel()
+	err := a.client.RunAction(ctx, action, id, signal)
+
+	outcome := fmt.Sprintf("[green]%s %s: done", action, name)
+	if signal != "" {
+		outcome = fmt.Sprintf("[green]%s %s (%s): done", action, name, signal)
+	}
+	if err != nil {
+		outcome = "[red]" + tview.Escape(err.Error())
+	}
+	a.app.QueueUpdateDraw(func() {
+		a.setOutcome(outcome)
+	})
+	a.refresh()
+}
+
+// setOutcome shows the outcome of an action in front of the status bar
+// text. It must run on the UI goroutine.
+func (a *App) setOutcome(outcome string) {
+	a.mu.Lock()
+	a.outcome = outcome
+	err := a.refreshErr
+	a.mu.Unlock()
+	a.statusBar.SetText(a.statusLine(err))
+}
+
+// statusLine returns the status bar text with the outcome of the last
+// action, if any
+func (a *App) statusLine(err error) string {
+	a.mu.RLock()
+	outcome := a.outcome
+	a.mu.RUnlock()
+	if outcome == "" {
+		return statusText(err)
+	}
+	return outcome + "[white]  " + statusText(err)
+}
+
+// handleConfirmInput lets the confirmation dialog handle its keys
+func (a *App) handleConfirmInput(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyCtrlC {
+		a.Stop()
+		return nil
+	}
+	return event
+}
+
+// openShell suspends the UI and opens a shell in the selected container
+// until it exits
+func (a *App) openShell() {
+	row, _ := a.table.GetSelection()
+
+	a.mu.RLock()
+	if row < 1 || row > len(a.containers) {
+		a.mu.RUnlock()
+		return
+	}
+	c := a.containers[row-1]
+	a.mu.RUnlock()
+
+	if a.client.ReadOnly() {
+		a.setOutcome("[red]" + docker.ErrReadOnly.Error())
+		return
+	}
+
+	var code int
+	var err error
+	a.app.Suspend(func() {
+		fmt.Printf("Connected to %s, exit the shell to return.\n", c.Name)
+		code, err = a.client.ExecTerminal(a.ctx, c.ID, a.shell, os.Stdin, os.Stdout)
+	})
+
+	switch {
+	case err != nil:
+		a.setOutcome("[red]" + tview.Escape(err.Error()))
+	case code != 0:
+		a.setOutcome(fmt.Sprintf("[yellow]shell in %s exited with code %d", c.Name, code))
+	default:
+		a.setOutcome(fmt.Sprintf("[gray]shell in %s exited", c.Name))
+	}
+	go a.refresh()
+}
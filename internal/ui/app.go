@@ -3,6 +3,7 @@ package ui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -12,20 +13,39 @@ import (
 	"github.com/tradik/cv-xslt/scripts/tools/stats/internal/docker"
 )
 
+// helpText lists the key bindings shown in the status bar
+const helpText = "[yellow]q[white]:Quit  [yellow]r[white]:Refresh  [yellow]c[white]:Sort CPU  [yellow]m[white]:Sort Mem  [yellow]n[white]:Sort Name  [yellow]x[white]:Sort Net/s  [yellow]b[white]:Sort Disk/s  [yellow]L[white]:CPU Host/Limit  [yellow]↑↓[white]:Navigate  [yellow]/[white]:Filter  [yellow]Enter[white]:Details  [yellow]l[white]:Logs  [yellow]S/T/P/K[white]:Stop/Restart/Pause/Kill  [yellow]e[white]:Exec"
+
 // App represents the main application
 type App struct {
 	client   *docker.Client
 	interval time.Duration
 	showAll  bool
-
-	app       *tview.Application
-	table     *tview.Table
-	infoBar   *tview.TextView
-	statusBar *tview.TextView
-
-	containers []docker.ContainerStats
+	shell    []string
+
+	app          *tview.Application
+	pages        *tview.Pages
+	mainFlex     *tview.Flex
+	table        *tview.Table
+	filterInput  *tview.InputField
+	infoBar      *tview.TextView
+	statusBar    *tview.TextView
+	detailTabBar *tview.TextView
+	detailView   *tview.TextView
+	logView      *tview.TextView
+	logSearch    *tview.InputField
+	logStatus    *tview.TextView
+
+	all        []docker.ContainerStats // Before the interactive filter
+	containers []docker.ContainerStats // Shown rows
+	query      string                  // Interactive filter
+	queryErr   error
 	sortField  docker.SortField
 	sortAsc    bool
+	detail     detailState // Container in the detail view; zero when closed
+	logs       logState    // Container in the log viewer; zero when closed
+	outcome    string      // Outcome of the last container action
+	refreshErr error       // Error of the last refresh, shown in the status bar
 	mu         sync.RWMutex
 
 	ctx    context.Context
@@ -42,11 +62,17 @@ func NewApp(client *docker.Client, interval time.Duration, showAll bool) *App {
 		showAll:   showAll,
 		sortField: docker.SortByCPU,
 		sortAsc:   false,
+		shell:     docker.DefaultShell,
 		ctx:       ctx,
 		cancel:    cancel,
 	}
 }
 
+// SetShell sets the command run by the exec shell
+func (a *App) SetShell(cmd []string) {
+	a.shell = cmd
+}
+
 // Run starts the application
 func (a *App) Run() error {
 	a.app = tview.NewApplication()
@@ -95,28 +121,62 @@ func (a *App) createUI() {
 	a.statusBar = tview.NewTextView().
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignCenter)
-	a.statusBar.SetText("[yellow]q[white]:Quit  [yellow]r[white]:Refresh  [yellow]c[white]:Sort CPU  [yellow]m[white]:Sort Mem  [yellow]n[white]:Sort Name  [yellow]↑↓[white]:Navigate")
+	a.statusBar.SetText(helpText)
 
-	// Layout
-	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+	// Layout; the filter prompt takes no space until opened
+	a.mainFlex = tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(a.infoBar, 5, 0, false).
 		AddItem(a.table, 0, 1, true).
+		AddItem(a.createFilterUI(), 0, 0, false).
 		AddItem(a.statusBar, 1, 0, false)
 
+	// The detail view is shown on top of the table
+	a.pages = tview.NewPages().
+		AddPage("main", a.mainFlex, true, true).
+		AddPage("detail", a.createDetailUI(), true, false).
+		AddPage("logs", a.createLogUI(), true, false)
+
 	// Set up key bindings
 	a.app.SetInputCapture(a.handleInput)
 
-	a.app.SetRoot(flex, true)
+	a.app.SetRoot(a.pages, true)
 }
 
 // handleInput handles keyboard input
 func (a *App) handleInput(event *tcell.EventKey) *tcell.EventKey {
+	if a.confirmOpen() {
+		return a.handleConfirmInput(event)
+	}
+	if a.filterOpen() {
+		if event.Key() == tcell.KeyCtrlC {
+			a.Stop()
+			return nil
+		}
+		return event
+	}
+	if a.logsOpen() {
+		return a.handleLogInput(event)
+	}
+	if a.detailOpen() {
+		return a.handleDetailInput(event)
+	}
+
 	switch event.Key() {
 	case tcell.KeyCtrlC:
 		a.Stop()
 		return nil
+	case tcell.KeyEnter:
+		a.openDetail()
+		return nil
+	case tcell.KeyEscape:
+		a.filterInput.SetText("")
+		a.closeFilter()
+		return nil
 	case tcell.KeyRune:
 		switch event.Rune() {
+		case '/':
+			a.openFilter()
+			return nil
 		case 'q', 'Q':
 			a.Stop()
 			return nil
@@ -132,11 +192,40 @@ func (a *App) handleInput(event *tcell.EventKey) *tcell.EventKey {
 		case 'n', 'N':
 			a.setSortField(docker.SortByName)
 			return nil
+		case 'x', 'X':
+			a.setSortField(docker.SortByNetRate)
+			return nil
+		case 'b', 'B':
+			a.setSortField(docker.SortByBlockRate)
+			return nil
+		case 'l':
+			a.openLogs()
+			return nil
+		case 'L':
+			a.toggleCPUMode()
+			return nil
+		case 'S', 'T', 'P', 'K':
+			a.requestAction(event.Rune())
+			return nil
+		case 'e':
+			a.openShell()
+			return nil
 		}
 	}
 	return event
 }
 
+// toggleCPUMode switches CPU percentages between host cores and the
+// container's CPU limit
+func (a *App) toggleCPUMode() {
+	if a.client.CPUMode() == docker.CPUModeLimit {
+		a.client.SetCPUMode(docker.CPUModeHost)
+	} else {
+		a.client.SetCPUMode(docker.CPUModeLimit)
+	}
+	go a.refresh()
+}
+
 // setSortField sets the sort field and refreshes the display
 func (a *App) setSortField(field docker.SortField) {
 	a.mu.Lock()
@@ -146,12 +235,14 @@ func (a *App) setSortField(field docker.SortField) {
 		a.sortField = field
 		a.sortAsc = false
 	}
-	docker.SortContainers(a.containers, a.sortField, a.sortAsc)
+	docker.SortContainers(a.all, a.sortField, a.sortAsc)
+	a.applyQuery()
 	a.mu.Unlock()
 	a.updateTable()
 }
 
-// refreshLoop periodically refreshes the statistics
+// refreshLoop periodically refreshes the statistics, and immediately
+// whenever the container set changes
 func (a *App) refreshLoop() {
 	ticker := time.NewTicker(a.interval)
 	defer ticker.Stop()
@@ -162,6 +253,8 @@ func (a *App) refreshLoop() {
 			return
 		case <-ticker.C:
 			a.refresh()
+		case <-a.client.Changes():
+			a.refresh()
 		}
 	}
 }
@@ -177,21 +270,29 @@ func (a *App) refresh() {
 		a.updateInfoBar(info)
 	}
 
-	// Get container stats
+	// Get container stats; a partial error still comes with usable rows
 	containers, err := a.client.GetContainerStats(ctx, a.showAll)
-	if err != nil {
+	a.mu.Lock()
+	a.refreshErr = err
+	a.mu.Unlock()
+	if err != nil && !docker.IsPartial(err) {
 		a.app.QueueUpdateDraw(func() {
 			a.statusBar.SetText(fmt.Sprintf("[red]Error: %v", err))
 		})
 		return
 	}
+	a.app.QueueUpdateDraw(func() {
+		a.statusBar.SetText(a.statusLine(err))
+	})
 
 	a.mu.Lock()
-	a.containers = containers
-	docker.SortContainers(a.containers, a.sortField, a.sortAsc)
+	a.all = containers
+	docker.SortContainers(a.all, a.sortField, a.sortAsc)
+	a.applyQuery()
 	a.mu.Unlock()
 
 	a.updateTable()
+	a.refreshDetail()
 }
 
 // updateInfoBar updates the Docker info bar
@@ -219,7 +320,11 @@ func (a *App) updateTable() {
 		a.table.Clear()
 
 		// Header row
-		headers := []string{"NAME", "STATUS", "CPU%", "MEM USAGE", "MEM%", "NET I/O", "BLOCK I/O", "PIDS", "IMAGE SIZE"}
+		cpuHeader := "CPU%"
+		if a.client.CPUMode() == docker.CPUModeLimit {
+			cpuHeader = "CPU% LIMIT"
+		}
+		headers := []string{"NAME", "STATUS", "HEALTH", "RESTARTS", "UPTIME", cpuHeader, "MEM USAGE", "MEM%", "NET I/O", "NET RATE", "BLOCK I/O", "BLOCK RATE", "PIDS", "IMAGE SIZE"}
 		for col, header := range headers {
 			cell := tview.NewTableCell(header).
 				SetTextColor(tcell.ColorYellow).
@@ -236,7 +341,11 @@ func (a *App) updateTable() {
 		defer a.mu.RUnlock()
 
 		if len(a.containers) == 0 {
-			cell := tview.NewTableCell("No containers found").
+			empty := "No containers found"
+			if len(a.all) > 0 {
+				empty = "No containers match the filter"
+			}
+			cell := tview.NewTableCell(empty).
 				SetTextColor(tcell.ColorGray).
 				SetAlign(tview.AlignCenter).
 				SetSelectable(false)
@@ -260,49 +369,146 @@ func (a *App) updateTable() {
 				SetTextColor(statusColor).
 				SetExpansion(1))
 
+			// Health, restarts and uptime come from inspect and are known
+			// even when usage statistics are not
+			a.table.SetCell(row+1, 2, tview.NewTableCell(formatHealth(cont)).
+				SetTextColor(getHealthColor(cont.Health)).
+				SetExpansion(1))
+			restartColor := tcell.ColorWhite
+			if cont.RestartCount > 0 {
+				restartColor = tcell.ColorYellow
+			}
+			a.table.SetCell(row+1, 3, tview.NewTableCell(fmt.Sprintf("%d", cont.RestartCount)).
+				SetTextColor(restartColor).
+				SetExpansion(1))
+			a.table.SetCell(row+1, 4, tview.NewTableCell(docker.FormatUptime(cont.Uptime())).
+				SetTextColor(tcell.ColorWhite).
+				SetExpansion(1))
+
+			// Stats could not be collected: show n/a and the reason
+			if cont.Unavailable() {
+				for col := 5; col < len(headers)-1; col++ {
+					a.table.SetCell(row+1, col, tview.NewTableCell("n/a").
+						SetTextColor(tcell.ColorRed).
+						SetExpansion(1))
+				}
+				a.table.SetCell(row+1, 6, tview.NewTableCell(cont.Error).
+					SetTextColor(tcell.ColorRed).
+					SetExpansion(1))
+				a.table.SetCell(row+1, len(headers)-1, tview.NewTableCell(docker.FormatBytesInt64(cont.ImageSize)).
+					SetTextColor(tcell.ColorPurple).
+					SetExpansion(1))
+				continue
+			}
+
 			// CPU%
 			cpuColor := getCPUColor(cont.CPUPercent)
-			a.table.SetCell(row+1, 2, tview.NewTableCell(docker.FormatPercent(cont.CPUPercent)).
+			a.table.SetCell(row+1, 5, tview.NewTableCell(docker.FormatPercent(cont.CPUPercent)).
 				SetTextColor(cpuColor).
 				SetExpansion(1))
 
 			// Memory Usage
-			a.table.SetCell(row+1, 3, tview.NewTableCell(docker.FormatMemUsage(cont.MemUsage, cont.MemLimit)).
+			a.table.SetCell(row+1, 6, tview.NewTableCell(docker.FormatMemUsage(cont.MemUsage, cont.MemLimit)).
 				SetTextColor(tcell.ColorWhite).
 				SetExpansion(1))
 
 			// Memory %
 			memColor := getMemColor(cont.MemPercent)
-			a.table.SetCell(row+1, 4, tview.NewTableCell(docker.FormatPercent(cont.MemPercent)).
+			a.table.SetCell(row+1, 7, tview.NewTableCell(docker.FormatPercent(cont.MemPercent)).
 				SetTextColor(memColor).
 				SetExpansion(1))
 
 			// Network I/O
-			a.table.SetCell(row+1, 5, tview.NewTableCell(docker.FormatNetIO(cont.NetRx, cont.NetTx)).
+			a.table.SetCell(row+1, 8, tview.NewTableCell(docker.FormatNetIO(cont.NetRx, cont.NetTx)).
+				SetTextColor(tcell.ColorTeal).
+				SetExpansion(1))
+
+			// Network rate
+			a.table.SetCell(row+1, 9, tview.NewTableCell(formatRatePair(cont.NetRxRate, cont.NetTxRate)).
 				SetTextColor(tcell.ColorTeal).
 				SetExpansion(1))
 
 			// Block I/O
-			a.table.SetCell(row+1, 6, tview.NewTableCell(docker.FormatBlockIO(cont.BlockRead, cont.BlockWrite)).
+			a.table.SetCell(row+1, 10, tview.NewTableCell(docker.FormatBlockIO(cont.BlockRead, cont.BlockWrite)).
+				SetTextColor(tcell.ColorBlue).
+				SetExpansion(1))
+
+			// Block rate
+			a.table.SetCell(row+1, 11, tview.NewTableCell(formatRatePair(cont.BlockReadRate, cont.BlockWriteRate)).
 				SetTextColor(tcell.ColorBlue).
 				SetExpansion(1))
 
 			// PIDs
-			a.table.SetCell(row+1, 7, tview.NewTableCell(fmt.Sprintf("%d", cont.PIDs)).
+			a.table.SetCell(row+1, 12, tview.NewTableCell(formatPIDs(cont.PIDs, cont.PIDsLimit)).
 				SetTextColor(tcell.ColorWhite).
 				SetExpansion(1))
 
 			// Image Size
-			a.table.SetCell(row+1, 8, tview.NewTableCell(docker.FormatBytesInt64(cont.ImageSize)).
+			a.table.SetCell(row+1, 13, tview.NewTableCell(docker.FormatBytesInt64(cont.ImageSize)).
 				SetTextColor(tcell.ColorPurple).
 				SetExpansion(1))
+
+			// Stale statistics show their last values dimmed
+			if cont.Stale {
+				for col := 5; col < len(headers)-1; col++ {
+					a.table.GetCell(row+1, col).SetTextColor(tcell.ColorGray)
+				}
+			}
 		}
 
 		// Update title with count and last update time
-		a.table.SetTitle(fmt.Sprintf(" Containers (%d) - Updated: %s ", len(a.containers), time.Now().Format("15:04:05")))
+		title := fmt.Sprintf(" Containers (%d) - Updated: %s ", len(a.containers), time.Now().Format("15:04:05"))
+		switch {
+		case a.queryErr != nil:
+			title = fmt.Sprintf(" Containers (%d) - [red]%s[-] - Updated: %s ", len(a.containers), tview.Escape(a.queryErr.Error()), time.Now().Format("15:04:05"))
+		case a.query != "":
+			title = fmt.Sprintf(" Containers (%d/%d) - Updated: %s ", len(a.containers), len(a.all), time.Now().Format("15:04:05"))
+		}
+		if filters := a.client.Filters(); len(filters) > 0 {
+			title += "- " + tview.Escape(docker.FormatFilters(filters)) + " "
+		}
+		if a.client.ReadOnly() {
+			title += "- read-only "
+		}
+		a.table.SetTitle(title)
 	})
 }
 
+// formatRatePair formats an inbound/outbound pair of throughput rates
+func formatRatePair(in, out float64) string {
+	return fmt.Sprintf("%s / %s", docker.FormatRate(in), docker.FormatRate(out))
+}
+
+// formatPIDs formats the process count with the PIDs limit, if any
+func formatPIDs(current, limit uint64) string {
+	if limit == 0 {
+		return fmt.Sprintf("%d", current)
+	}
+	return fmt.Sprintf("%d/%d", current, limit)
+}
+
+// formatHealth formats the health check status with the failing streak
+func formatHealth(cont docker.ContainerStats) string {
+	switch {
+	case cont.Health == "":
+		return "-"
+	case cont.HealthFailingStreak > 0:
+		return fmt.Sprintf("%s (%d)", cont.Health, cont.HealthFailingStreak)
+	default:
+		return cont.Health
+	}
+}
+
+// statusText returns the status bar text, prefixed by a warning when
+// statistics of some containers could not be collected
+func statusText(err error) string {
+	var partial *docker.PartialError
+	if errors.As(err, &partial) {
+		return fmt.Sprintf("[red]Stats unavailable for %d container(s)[white]  %s", len(partial.Failures), helpText)
+	}
+	return helpText
+}
+
 // getCPUColor returns color based on CPU usage
 func getCPUColor(percent float64) tcell.Color {
 	switch {
@@ -317,6 +523,20 @@ func getCPUColor(percent float64) tcell.Color {
 	}
 }
 
+// getHealthColor returns color based on the health check status
+func getHealthColor(health string) tcell.Color {
+	switch health {
+	case "healthy":
+		return tcell.ColorGreen
+	case "unhealthy":
+		return tcell.ColorRed
+	case "starting":
+		return tcell.ColorYellow
+	default:
+		return tcell.ColorGray
+	}
+}
+
 // getMemColor returns color based on memory usage
 func getMemColor(percent float64) tcell.Color {
 	switch {
@@ -1,9 +1,13 @@
 package ui
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/tradik/cv-xslt/scripts/tools/stats/internal/docker"
 )
 
 func TestGetCPUColor(t *testing.T) {
@@ -50,3 +54,155 @@ func TestGetMemColor(t *testing.T) {
 		})
 	}
 }
+
+func TestStatusText(t *testing.T) {
+	if got := statusText(nil); got != helpText {
+		t.Errorf("statusText(nil) = %q; want help text", got)
+	}
+
+	partial := &docker.PartialError{
+		Total:    3,
+		Failures: []docker.ContainerError{{Name: "web", Err: errors.New("boom")}},
+	}
+	got := statusText(partial)
+	if !strings.Contains(got, "unavailable for 1 container") || !strings.HasSuffix(got, helpText) {
+		t.Errorf("statusText(partial) = %q; want warning followed by help text", got)
+	}
+}
+
+func TestSetOutcomeKeepsWarning(t *testing.T) {
+	a := &App{statusBar: tview.NewTextView().SetDynamicColors(true)}
+	a.refreshErr = &docker.PartialError{
+		Total:    2,
+		Failures: []docker.ContainerError{{Name: "web", Err: errors.New("boom")}},
+	}
+	a.setOutcome("[gray]stopping web...")
+	got := a.statusBar.GetText(true)
+	if !strings.HasPrefix(got, "stopping web...") || !strings.Contains(got, "unavailable for 1 container") {
+		t.Errorf("status bar = %q; want the outcome followed by the partial-error warning", got)
+	}
+}
+
+func TestFormatHealth(t *testing.T) {
+	tests := []struct {
+		name     string
+		cont     docker.ContainerStats
+		expected string
+	}{
+		{"no health check", docker.ContainerStats{}, "-"},
+		{"healthy", docker.ContainerStats{Health: "healthy"}, "healthy"},
+		{"failing", docker.ContainerStats{Health: "unhealthy", HealthFailingStreak: 3}, "unhealthy (3)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := formatHealth(tt.cont); result != tt.expected {
+				t.Errorf("formatHealth() = %q; want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatPIDs(t *testing.T) {
+	if result := formatPIDs(12, 0); result != "12" {
+		t.Errorf("formatPIDs(12, 0) = %q; want 12", result)
+	}
+	if result := formatPIDs(12, 100); result != "12/100" {
+		t.Errorf("formatPIDs(12, 100) = %q; want 12/100", result)
+	}
+}
+
+func TestDetailText(t *testing.T) {
+	procs := docker.ProcessList{
+		Titles:    []string{"PID", "CMD"},
+		Processes: [][]string{{"1", "nginx: master"}, {"12345", "nginx: worker"}},
+	}
+	text := processesText(procs, nil)
+	if !strings.Contains(text, "1     nginx: master") || !strings.Contains(text, "12345 nginx: worker") {
+		t.Errorf("processesText() did not align columns:\n%s", text)
+	}
+	if !strings.Contains(text, noHostStats) {
+		t.Errorf("processesText() without host stats = %q; want a note on %%CPU and RSS", text)
+	}
+	procs.HostStats = true
+	if text := processesText(procs, nil); strings.Contains(text, noHostStats) {
+		t.Errorf("processesText() with host stats = %q; want no note", text)
+	}
+	// Rows longer than the titles are shown without padding the extra values
+	procs.Processes = append(procs.Processes, []string{"7", "sh", "-c", "sleep 1"})
+	if text := processesText(procs, nil); !strings.Contains(text, "-c sleep 1\n") {
+		t.Errorf("processesText() with a long row did not keep its values:\n%s", text)
+	}
+	if text := processesText(docker.ProcessList{}, errors.New("container is not running")); !strings.Contains(text, "not running") {
+		t.Errorf("processesText() with error = %q; want the error", text)
+	}
+
+	if text := healthText(docker.ContainerDetails{}); !strings.Contains(text, "No health check") {
+		t.Errorf("healthText() without health check = %q", text)
+	}
+	details := docker.ContainerDetails{
+		Healthcheck: []string{"CMD", "true"},
+		HealthLog: []docker.HealthResult{
+			{ExitCode: 0, Output: "first"},
+			{ExitCode: 1, Output: "second"},
+		},
+	}
+	text = healthText(details)
+	if strings.Index(text, "second") > strings.Index(text, "first") {
+		t.Errorf("healthText() is not newest first:\n%s", text)
+	}
+
+	// Stale statistics keep their values next to the reason
+	stale := &docker.ContainerStats{State: "running", CPUPercent: 42, Error: "stats are stale: last sample 1m0s ago", Stale: true}
+	if text := metricsText(stale); !strings.Contains(text, "last sample") || !strings.Contains(text, "42.00%") {
+		t.Errorf("metricsText(stale) = %q; want the reason and the last values", text)
+	}
+
+	if text := metricsText(nil); !strings.Contains(text, "no longer listed") {
+		t.Errorf("metricsText(nil) = %q", text)
+	}
+}
+
+func TestLogState(t *testing.T) {
+	var s logState
+	batch := make([]docker.LogLine, maxLogLines)
+	for i := range batch {
+		batch[i] = docker.LogLine{Text: "ok"}
+	}
+	s.appendLines(batch)
+	s.appendLines([]docker.LogLine{{Text: "Connection REFUSED"}, {Text: "ok"}})
+	if len(s.lines) != maxLogLines {
+		t.Fatalf("buffer holds %d lines; want %d", len(s.lines), maxLogLines)
+	}
+
+	s.query = "refused"
+	matches := s.matches()
+	if len(matches) != 1 || matches[0] != maxLogLines-2 {
+		t.Errorf("matches() = %v; want [%d]", matches, maxLogLines-2)
+	}
+
+	// Lines held while paused are bounded the same way
+	s.appendPending(batch)
+	s.appendPending([]docker.LogLine{{Text: "newest"}})
+	if len(s.pending) != maxLogLines || s.pending[maxLogLines-1].Text != "newest" {
+		t.Errorf("pending holds %d lines ending %q; want %d ending with the newest", len(s.pending),
+			s.pending[len(s.pending)-1].Text, maxLogLines)
+	}
+}
+
+func TestApplyQuery(t *testing.T) {
+	a := &App{all: []docker.ContainerStats{{Name: "shop-web"}, {Name: "shop-db"}, {Name: "blog"}}}
+
+	a.query = "shop"
+	a.applyQuery()
+	if len(a.containers) != 2 || a.queryErr != nil {
+		t.Errorf("applyQuery(shop) shows %d rows, error %v; want 2 rows", len(a.containers), a.queryErr)
+	}
+
+	// An invalid regular expression shows every row while it is typed
+	a.query = "~shop["
+	a.applyQuery()
+	if len(a.containers) != 3 || a.queryErr == nil {
+		t.Errorf("applyQuery(~shop[) shows %d rows, error %v; want 3 rows and an error", len(a.containers), a.queryErr)
+	}
+}
@@ -0,0 +1,419 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/tradik/cv-xslt/scripts/tools/stats/internal/docker"
+)
+
+// detailHelpText lists the key bindings of the detail view
+const detailHelpText = "[yellow]Tab[white]:Next Tab  [yellow]1-4[white]:Select Tab  [yellow]↑↓[white]:Scroll  [yellow]r[white]:Refresh  [yellow]Esc[white]:Back  [yellow]q[white]:Quit"
+
+// detailTabs are the tab titles of the detail view in display order
+var detailTabs = []string{"Metrics", "Inspect", "Health", "Processes"}
+
+// noHostStats explains missing %CPU and RSS columns in the process table
+const noHostStats = "%CPU and RSS are shown when running on the Docker host"
+
+// detailState holds the container shown in the detail view and the data
+// fetched for it
+type detailState struct {
+	id      string
+	name    string
+	tab     int
+	loaded  bool
+	details docker.ContainerDetails
+	procs   docker.ProcessList
+	err     error
+	procErr error
+}
+
+// createDetailUI creates the detail view components
+func (a *App) createDetailUI() tview.Primitive {
+	a.detailTabBar = tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(false)
+
+	a.detailView = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false)
+	a.detailView.SetBorder(true)
+
+	status := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(detailHelpText)
+
+	return tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(a.detailTabBar, 1, 0, false).
+		AddItem(a.detailView, 0, 1, true).
+		AddItem(status, 1, 0, false)
+}
+
+// detailOpen reports whether the detail view is shown
+func (a *App) detailOpen() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.detail.id != ""
+}
+
+// openDetail shows the detail view for the selected container
+func (a *App) openDetail() {
+	row, _ := a.table.GetSelection()
+
+	a.mu.Lock()
+	if row < 1 || row > len(a.containers) {
+		a.mu.Unlock()
+		return
+	}
+	c := a.containers[row-1]
+	a.detail = detailState{id: c.ID, name: c.Name}
+	a.mu.Unlock()
+
+	a.renderDetail()
+	a.pages.ShowPage("detail")
+	a.app.SetFocus(a.detailView)
+	go a.refreshDetail()
+}
+
+// closeDetail returns to the table; its selection is left untouched
+func (a *App) closeDetail() {
+	a.mu.Lock()
+	a.detail = detailState{}
+	a.mu.Unlock()
+
+	a.pages.HidePage("detail")
+	a.app.SetFocus(a.table)
+}
+
+// handleDetailInput handles keyboard input while the detail view is shown
+func (a *App) handleDetailInput(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyCtrlC:
+		a.Stop()
+		return nil
+	case tcell.KeyEscape, tcell.KeyBackspace, tcell.KeyBackspace2:
+		a.closeDetail()
+		return nil
+	case tcell.KeyTab, tcell.KeyRight:
+		a.setDetailTab(1)
+		return nil
+	case tcell.KeyBacktab, tcell.KeyLeft:
+		a.setDetailTab(-1)
+		return nil
+	case tcell.KeyRune:
+		switch r := event.Rune(); r {
+		case 'q', 'Q':
+			a.Stop()
+			return nil
+		case 'r', 'R':
+			go a.refreshDetail()
+			return nil
+		case '1', '2', '3', '4':
+			a.mu.Lock()
+			a.detail.tab = int(r - '1')
+			a.mu.Unlock()
+			a.renderDetail()
+			a.detailView.ScrollToBeginning()
+			return nil
+		}
+	}
+	// Arrow keys and paging scroll the text view
+	return event
+}
+
+// setDetailTab moves to the next or previous tab
+func (a *App) setDetailTab(delta int) {
+	a.mu.Lock()
+	a.detail.tab = (a.detail.tab + delta + len(detailTabs)) % len(detailTabs)
+	a.mu.Unlock()
+	a.renderDetail()
+	a.detailView.ScrollToBeginning()
+}
+
+// refreshDetail fetches inspect data and processes of the container in the
+// detail view and redraws it
+func (a *App) refreshDetail() {
+	a.mu.RLock()
+	id := a.detail.id
+	a.mu.RUnlock()
+	if id == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(a.ctx, 5*time.Second)
+	defer cancel()
+	details, err := a.client.GetContainerDetails(ctx, id)
+	procs, procErr := a.client.GetProcesses(ctx, id)
+
+	a.mu.Lock()
+	if a.detail.id != id {
+		// Closed or switched while fetching
+		a.mu.Unlock()
+		return
+	}
+	a.detail.details, a.detail.procs = details, procs
+	a.detail.err, a.detail.procErr = err, procErr
+	a.detail.loaded = true
+	a.mu.Unlock()
+
+	a.app.QueueUpdateDraw(a.renderDetail)
+}
+
+// renderDetail redraws the tab bar and the content of the current tab. It
+// must run on the UI goroutine.
+func (a *App) renderDetail() {
+	a.mu.RLock()
+	d := a.detail
+	var stats *docker.ContainerStats
+	for i := range a.all {
+		if a.all[i].ID == d.id {
+			c := a.all[i]
+			stats = &c
+			break
+		}
+	}
+	a.mu.RUnlock()
+
+	if d.id == "" {
+		return
+	}
+
+	var tabs strings.Builder
+	for i, title := range detailTabs {
+		if i == d.tab {
+			fmt.Fprintf(&tabs, "[black:yellow] %d %s [-:-] ", i+1, title)
+		} else {
+			fmt.Fprintf(&tabs, "[gray] %d %s [-] ", i+1, title)
+		}
+	}
+	a.detailTabBar.SetText(tabs.String())
+	a.detailView.SetTitle(fmt.Sprintf(" %s (%s) ", tview.Escape(d.name), d.id))
+
+	var text string
+	switch {
+	case d.tab == 0:
+		text = metricsText(stats)
+	case !d.loaded:
+		text = "[gray]Loading..."
+	case d.tab == 3:
+		text = processesText(d.procs, d.procErr)
+	case d.err != nil:
+		text = "[red]" + tview.Escape(d.err.Error())
+	case d.tab == 1:
+		text = inspectText(d.details)
+	case d.tab == 2:
+		text = healthText(d.details)
+	}
+	a.detailView.SetText(text)
+}
+
+// metricsText shows the live statistics of a container
+func metricsText(c *docker.ContainerStats) string {
+	if c == nil {
+		return "[gray]Container is no longer listed"
+	}
+	if c.Unavailable() {
+		return "[red]n/a: " + tview.Escape(c.Error)
+	}
+
+	var b strings.Builder
+	if c.Stale {
+		b.WriteString("[yellow]" + tview.Escape(c.Error) + "\n")
+	}
+	line := func(label, format string, args ...any) {
+		fmt.Fprintf(&b, "[gray]%-12s[white]"+format+"\n", append([]any{label}, args...)...)
+	}
+
+	line("State", "%s  health %s  restarts %d (%s)  uptime %s",
+		c.State, formatHealth(*c), c.RestartCount, c.RestartPolicy, docker.FormatUptime(c.Uptime()))
+	limit := "unlimited"
+	if c.CPULimit > 0 {
+		limit = fmt.Sprintf("%.2f CPUs", c.CPULimit)
+	}
+	line("CPU", "%s (host %s)  limit %s  throttled %.1f%% (%d periods, %s)",
+		docker.FormatPercent(c.CPUPercent), docker.FormatPercent(c.CPUPercentHost), limit,
+		c.ThrottledPercent, c.ThrottledPeriods, c.ThrottledTime.Round(time.Millisecond))
+	if len(c.PerCPUPercent) > 0 {
+		cores := make([]string, len(c.PerCPUPercent))
+		for i, p := range c.PerCPUPercent {
+			cores[i] = fmt.Sprintf("cpu%d %.0f%%", i, p)
+		}
+		line("Cores", "%s", strings.Join(cores, "  "))
+	}
+	line("Memory", "%s (%.1f%%)  raw %s  peak %s",
+		docker.FormatMemUsage(c.MemUsage, c.MemLimit), c.MemPercent,
+		docker.FormatBytes(c.MemUsageRaw), docker.FormatBytes(c.MemMaxUsage))
+	line("", "anon %s  cache %s  shmem %s  swap %s  failcnt %d  OOM kills %d",
+		docker.FormatBytes(c.MemAnon), docker.FormatBytes(c.MemFile), docker.FormatBytes(c.MemShmem),
+		docker.FormatBytes(c.MemSwap), c.MemFailcnt, c.OOMKills)
+	line("PIDs", "%s", formatPIDs(c.PIDs, c.PIDsLimit))
+
+	for _, n := range c.Networks {
+		network := n.Network
+		if network == "" {
+			network = "?"
+		}
+		line("Net "+n.Name, "%s  %s  pkts %d/%d  err %d/%d  drop %d/%d",
+			tview.Escape(network), formatRatePair(n.RxRate, n.TxRate),
+			n.RxPackets, n.TxPackets, n.RxErrors, n.TxErrors, n.RxDropped, n.TxDropped)
+	}
+	for _, d := range c.Devices {
+		line("Disk "+d.Name, "%s  iops %.0f/%.0f  total %s",
+			formatRatePair(d.ReadRate, d.WriteRate), d.ReadIOPS, d.WriteIOPS,
+			docker.FormatBlockIO(d.ReadBytes, d.WriteBytes))
+	}
+	return b.String()
+}
+
+// inspectText shows the configuration of a container
+func inspectText(d docker.ContainerDetails) string {
+	var b strings.Builder
+	heading := func(title string) { fmt.Fprintf(&b, "\n[yellow]%s[white]\n", title) }
+	field := func(name, value string) { fmt.Fprintf(&b, "  [gray]%-12s[white]%s\n", name, tview.Escape(value)) }
+	item := func(value string) { fmt.Fprintf(&b, "  %s\n", tview.Escape(value)) }
+	limit := func(set bool, value string) string {
+		if !set {
+			return "unlimited"
+		}
+		return value
+	}
+
+	heading("Container")
+	field("Image", d.Image)
+	field("Command", strings.Join(d.Command, " "))
+	field("WorkingDir", d.WorkingDir)
+	field("User", d.User)
+
+	heading("Limits")
+	field("CPUs", limit(d.Limits.CPUs > 0, fmt.Sprintf("%.2f", d.Limits.CPUs)))
+	field("CPU shares", limit(d.Limits.CPUShares > 0, fmt.Sprintf("%d", d.Limits.CPUShares)))
+	field("cpuset", limit(d.Limits.CPUSet != "", d.Limits.CPUSet))
+	field("Memory", limit(d.Limits.Memory > 0, docker.FormatBytesInt64(d.Limits.Memory)))
+	field("Reservation", limit(d.Limits.MemoryReservation > 0, docker.FormatBytesInt64(d.Limits.MemoryReservation)))
+	field("Swap", limit(d.Limits.MemorySwap > 0, docker.FormatBytesInt64(d.Limits.MemorySwap)))
+	field("PIDs", limit(d.Limits.PidsLimit > 0, fmt.Sprintf("%d", d.Limits.PidsLimit)))
+
+	heading("Ports")
+	for _, p := range d.Ports {
+		if p.HostPort == "" {
+			item(p.ContainerPort + " (not published)")
+		} else {
+			item(fmt.Sprintf("%s:%s → %s", p.HostIP, p.HostPort, p.ContainerPort))
+		}
+	}
+
+	heading("Networks")
+	for _, n := range d.Networks {
+		line := fmt.Sprintf("%-16s %-16s gw %-16s mac %s", n.Name, n.IPAddress, n.Gateway, n.MacAddress)
+		if len(n.Aliases) > 0 {
+			line += "  aliases " + strings.Join(n.Aliases, ",")
+		}
+		item(line)
+	}
+
+	heading("Mounts")
+	for _, m := range d.Mounts {
+		mode := "ro"
+		if m.ReadWrite {
+			mode = "rw"
+		}
+		item(fmt.Sprintf("%-7s %s → %s (%s)", m.Type, m.Source, m.Destination, mode))
+	}
+
+	heading("Environment")
+	for _, env := range d.Env {
+		item(env)
+	}
+
+	heading("Labels")
+	for _, key := range d.SortedLabels() {
+		item(key + "=" + d.Labels[key])
+	}
+	return strings.TrimPrefix(b.String(), "\n")
+}
+
+// healthText shows the health check command and its latest results,
+// newest first
+func healthText(d docker.ContainerDetails) string {
+	if len(d.Healthcheck) == 0 {
+		return "[gray]No health check configured"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[gray]Test[white]  %s\n\n", tview.Escape(strings.Join(d.Healthcheck, " ")))
+	if len(d.HealthLog) == 0 {
+		b.WriteString("[gray]No results yet")
+		return b.String()
+	}
+	for i := len(d.HealthLog) - 1; i >= 0; i-- {
+		r := d.HealthLog[i]
+		color := "green"
+		if r.ExitCode != 0 {
+			color = "red"
+		}
+		fmt.Fprintf(&b, "%s [%s]exit %d[gray] in %s[white]\n",
+			r.Start.Local().Format("2006-01-02 15:04:05"), color, r.ExitCode, r.End.Sub(r.Start).Round(time.Millisecond))
+		for _, out := range strings.Split(strings.TrimRight(r.Output, "\n"), "\n") {
+			if out != "" {
+				fmt.Fprintf(&b, "  %s\n", tview.Escape(out))
+			}
+		}
+	}
+	return b.String()
+}
+
+// processesText shows the process table of a container
+func processesText(procs docker.ProcessList, err error) string {
+	if err != nil {
+		return "[red]" + tview.Escape(err.Error())
+	}
+	if len(procs.Processes) == 0 {
+		return "[gray]No processes"
+	}
+
+	widths := make([]int, len(procs.Titles))
+	for i, title := range procs.Titles {
+		widths[i] = len(title)
+	}
+	for _, proc := range procs.Processes {
+		for i, value := range proc {
+			if i < len(widths) {
+				widths[i] = max(widths[i], len(value))
+			}
+		}
+	}
+
+	var b strings.Builder
+	// Rows may have more values than titles
+	row := func(values []string) {
+		for i, value := range values {
+			width := 0
+			if i < len(widths) {
+				width = widths[i]
+			}
+			if i == len(values)-1 {
+				b.WriteString(tview.Escape(value))
+			} else {
+				fmt.Fprintf(&b, "%-*s ", width, tview.Escape(value))
+			}
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("[yellow]")
+	row(procs.Titles)
+	b.WriteString("[white]")
+	for _, proc := range procs.Processes {
+		row(proc)
+	}
+	if !procs.HostStats {
+		b.WriteString("\n[gray]" + tview.Escape(noHostStats))
+	}
+	return b.String()
+}
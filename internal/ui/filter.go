@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/tradik/cv-xslt/scripts/tools/stats/internal/docker"
+)
+
+// createFilterUI creates the filter prompt opened with /
+func (a *App) createFilterUI() *tview.InputField {
+	a.filterInput = tview.NewInputField().
+		SetLabel("/").
+		SetFieldBackgroundColor(tcell.ColorDefault)
+
+	// Incremental: the rows narrow as the query changes
+	a.filterInput.SetChangedFunc(func(text string) {
+		a.mu.Lock()
+		a.query = text
+		a.applyQuery()
+		a.mu.Unlock()
+		a.updateTable()
+	})
+	a.filterInput.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			a.filterInput.SetText("")
+		}
+		a.closeFilter()
+	})
+	return a.filterInput
+}
+
+// openFilter shows the filter prompt
+func (a *App) openFilter() {
+	a.mainFlex.ResizeItem(a.filterInput, 1, 0)
+	a.app.SetFocus(a.filterInput)
+}
+
+// closeFilter returns to the table, keeping the prompt visible while a
+// query is active
+func (a *App) closeFilter() {
+	if a.filterInput.GetText() == "" {
+		a.mainFlex.ResizeItem(a.filterInput, 0, 0)
+	}
+	a.app.SetFocus(a.table)
+}
+
+// filterOpen reports whether the filter prompt has the focus
+func (a *App) filterOpen() bool {
+	return a.app.GetFocus() == a.filterInput
+}
+
+// applyQuery narrows the rows to the containers matching the query. An
+// invalid query shows every container until it is fixed. The caller must
+// hold the lock.
+func (a *App) applyQuery() {
+	q, err := docker.ParseQuery(a.query)
+	a.queryErr = err
+	if err != nil {
+		a.containers = a.all
+		return
+	}
+	a.containers = docker.FilterContainers(a.all, q)
+}
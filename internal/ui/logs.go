@@ -0,0 +1,354 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/tradik/cv-xslt/scripts/tools/stats/internal/docker"
+)
+
+const (
+	// logTail is how many past lines are loaded when the log viewer opens
+	logTail = 500
+	// maxLogLines bounds the log buffer; older lines are dropped
+	maxLogLines = 5000
+	// logBatch bounds how many lines are added per redraw
+	logBatch = 256
+)
+
+// logHelpText lists the key bindings of the log viewer
+const logHelpText = "[yellow]/[white]:Search  [yellow]n/N[white]:Next/Prev Match  [yellow]t[white]:Timestamps  [yellow]Space[white]:Pause  [yellow]↑↓[white]:Scroll  [yellow]G[white]:Follow  [yellow]Esc[white]:Back"
+
+// logState holds the container shown in the log viewer and its output
+type logState struct {
+	id         string
+	name       string
+	follower   *docker.LogFollower
+	lines      []docker.LogLine
+	pending    []docker.LogLine // Received while paused
+	paused     bool
+	timestamps bool
+	follow     bool // Keep the newest line in view
+	query      string
+	match      int // Index into the matching lines of the current match
+	ended      bool
+	err        error
+}
+
+// createLogUI creates the log viewer components
+func (a *App) createLogUI() tview.Primitive {
+	a.logView = tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetScrollable(true).
+		SetWrap(false)
+	a.logView.SetBorder(true)
+
+	a.logStatus = tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(logHelpText)
+
+	a.logSearch = tview.NewInputField().
+		SetLabel("/").
+		SetFieldBackgroundColor(tcell.ColorDefault)
+	a.logSearch.SetChangedFunc(func(text string) {
+		a.mu.Lock()
+		a.logs.query, a.logs.match = text, -1
+		a.mu.Unlock()
+		// Incremental: show the newest match as the query changes
+		a.renderLogs()
+		a.moveMatch(-1)
+	})
+	a.logSearch.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			a.logSearch.SetText("")
+		}
+		a.app.SetFocus(a.logView)
+	})
+
+	return tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(a.logView, 0, 1, true).
+		AddItem(a.logSearch, 1, 0, false).
+		AddItem(a.logStatus, 1, 0, false)
+}
+
+// logsOpen reports whether the log viewer is shown
+func (a *App) logsOpen() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.logs.id != ""
+}
+
+// openLogs shows the log viewer for the selected container and starts
+// following its output
+func (a *App) openLogs() {
+	row, _ := a.table.GetSelection()
+
+	a.mu.Lock()
+	if row < 1 || row > len(a.containers) {
+		a.mu.Unlock()
+		return
+	}
+	c := a.containers[row-1]
+	a.logs = logState{id: c.ID, name: c.Name, follow: true, match: -1}
+	a.mu.Unlock()
+
+	a.logSearch.SetText("")
+	a.renderLogs()
+	a.pages.ShowPage("logs")
+	a.app.SetFocus(a.logView)
+	go a.followLogs(c.ID)
+}
+
+// closeLogs stops following and returns to the table
+func (a *App) closeLogs() {
+	a.mu.Lock()
+	if a.logs.follower != nil {
+		a.logs.follower.Close()
+	}
+	a.logs = logState{}
+	a.mu.Unlock()
+
+	a.pages.HidePage("logs")
+	a.app.SetFocus(a.table)
+}
+
+// followLogs reads log lines until the stream ends or the viewer closes
+func (a *App) followLogs(id string) {
+	follower, err := a.client.FollowLogs(context.Background(), id, logTail)
+
+	a.mu.Lock()
+	if a.logs.id != id {
+		a.mu.Unlock()
+		if follower != nil {
+			follower.Close()
+		}
+		return
+	}
+	a.logs.follower, a.logs.err, a.logs.ended = follower, err, err != nil
+	a.mu.Unlock()
+	if err != nil {
+		a.app.QueueUpdateDraw(a.renderLogs)
+		return
+	}
+
+	for line := range follower.Lines() {
+		batch := []docker.LogLine{line}
+	drain:
+		for len(batch) < logBatch {
+			select {
+			case line, ok := <-follower.Lines():
+				if !ok {
+					break drain
+				}
+				batch = append(batch, line)
+			default:
+				break drain
+			}
+		}
+
+		a.mu.Lock()
+		if a.logs.id != id {
+			a.mu.Unlock()
+			return
+		}
+		if a.logs.paused {
+			a.logs.appendPending(batch)
+		} else {
+			a.logs.appendLines(batch)
+		}
+		a.mu.Unlock()
+		a.app.QueueUpdateDraw(a.renderLogs)
+	}
+
+	a.mu.Lock()
+	if a.logs.id == id {
+		a.logs.ended, a.logs.err = true, follower.Err()
+	}
+	a.mu.Unlock()
+	a.app.QueueUpdateDraw(a.renderLogs)
+}
+
+// appendLines adds lines to the buffer, dropping the oldest ones
+func (s *logState) appendLines(lines []docker.LogLine) {
+	s.lines = capLines(append(s.lines, lines...))
+}
+
+// appendPending holds lines received while paused, dropping the oldest ones
+func (s *logState) appendPending(lines []docker.LogLine) {
+	s.pending = capLines(append(s.pending, lines...))
+}
+
+// capLines drops the oldest lines beyond maxLogLines
+func capLines(lines []docker.LogLine) []docker.LogLine {
+	if over := len(lines) - maxLogLines; over > 0 {
+		return append([]docker.LogLine(nil), lines[over:]...)
+	}
+	return lines
+}
+
+// handleLogInput handles keyboard input while the log viewer is shown
+func (a *App) handleLogInput(event *tcell.EventKey) *tcell.EventKey {
+	// Let the search field have its keys
+	if a.app.GetFocus() == a.logSearch {
+		return event
+	}
+
+	switch event.Key() {
+	case tcell.KeyCtrlC:
+		a.Stop()
+		return nil
+	case tcell.KeyEscape:
+		a.closeLogs()
+		return nil
+	case tcell.KeyEnd:
+		a.setFollow(true)
+		return nil
+	case tcell.KeyUp, tcell.KeyPgUp, tcell.KeyHome:
+		a.setFollow(false)
+		return event
+	case tcell.KeyRune:
+		switch event.Rune() {
+		case 'q':
+			a.closeLogs()
+			return nil
+		case 't':
+			a.mu.Lock()
+			a.logs.timestamps = !a.logs.timestamps
+			a.mu.Unlock()
+			a.renderLogs()
+			return nil
+		case ' ', 'p':
+			a.mu.Lock()
+			a.logs.paused = !a.logs.paused
+			if !a.logs.paused {
+				a.logs.appendLines(a.logs.pending)
+				a.logs.pending = nil
+			}
+			a.mu.Unlock()
+			a.renderLogs()
+			return nil
+		case '/':
+			a.app.SetFocus(a.logSearch)
+			return nil
+		case 'n':
+			a.moveMatch(-1)
+			return nil
+		case 'N':
+			a.moveMatch(1)
+			return nil
+		case 'G':
+			a.setFollow(true)
+			return nil
+		case 'k':
+			a.setFollow(false)
+			return event
+		}
+	}
+	return event
+}
+
+// setFollow turns keeping the newest line in view on or off
+func (a *App) setFollow(follow bool) {
+	a.mu.Lock()
+	a.logs.follow = follow
+	a.mu.Unlock()
+	if follow {
+		a.renderLogs()
+	}
+}
+
+// moveMatch highlights the previous (direction -1, towards older lines) or
+// next matching line and scrolls to it
+func (a *App) moveMatch(direction int) {
+	a.mu.Lock()
+	matches := a.logs.matches()
+	if len(matches) == 0 {
+		a.mu.Unlock()
+		a.logView.Highlight()
+		return
+	}
+	match := a.logs.match
+	switch {
+	case match < 0 || match >= len(matches):
+		match = len(matches) - 1
+	case direction < 0 && match > 0:
+		match--
+	case direction > 0 && match < len(matches)-1:
+		match++
+	}
+	a.logs.match = match
+	a.logs.follow = false
+	a.mu.Unlock()
+
+	a.logView.Highlight(fmt.Sprintf("line%d", matches[match]))
+	a.logView.ScrollToHighlight()
+}
+
+// matches returns the indexes of the lines matching the query
+func (s *logState) matches() []int {
+	if s.query == "" {
+		return nil
+	}
+	query := strings.ToLower(s.query)
+	var matches []int
+	for i, line := range s.lines {
+		if strings.Contains(strings.ToLower(line.Text), query) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// renderLogs redraws the log viewer. It must run on the UI goroutine.
+func (a *App) renderLogs() {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	s := &a.logs
+	if s.id == "" {
+		return
+	}
+
+	status := "[green]following"
+	switch {
+	case s.err != nil:
+		status = "[red]" + tview.Escape(s.err.Error())
+	case s.ended:
+		status = "[gray]stream ended"
+	case s.paused:
+		status = fmt.Sprintf("[yellow]paused (+%d)", len(s.pending))
+	case !s.follow:
+		status = "[yellow]scrolled"
+	}
+	a.logView.SetTitle(fmt.Sprintf(" Logs: %s - %s[-] ", tview.Escape(s.name), status))
+
+	query := strings.ToLower(s.query)
+	var b strings.Builder
+	for i, line := range s.lines {
+		match := query != "" && strings.Contains(strings.ToLower(line.Text), query)
+		if match {
+			fmt.Fprintf(&b, `["line%d"]`, i)
+		}
+		if s.timestamps && !line.Time.IsZero() {
+			fmt.Fprintf(&b, "[gray]%s[-] ", line.Time.Local().Format("15:04:05.000"))
+		}
+		if line.Source == docker.Stderr {
+			b.WriteString("[red]")
+		} else {
+			b.WriteString("[white]")
+		}
+		b.WriteString(tview.Escape(line.Text))
+		if match {
+			b.WriteString(`[""]`)
+		}
+		b.WriteString("\n")
+	}
+	a.logView.SetText(b.String())
+	if s.follow {
+		a.logView.ScrollToEnd()
+	}
+}
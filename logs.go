@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradik/cv-xslt/scripts/tools/stats/internal/docker"
+)
+
+const (
+	// logTail is how many past lines are loaded when the log viewer opens
+	logTail = 500
+	// maxLogLines bounds the log buffer; older lines are dropped
+	maxLogLines = 5000
+	// logBatch bounds how many lines are delivered per message
+	logBatch = 256
+)
+
+var matchStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("3"))
+
+// logView is the state of the log viewer opened with l
+type logView struct {
+	open       bool
+	id         string
+	name       string
+	follower   *docker.LogFollower
+	lines      []docker.LogLine
+	pending    []docker.LogLine // Received while paused
+	paused     bool
+	timestamps bool
+	scroll     int // Lines scrolled up from the newest line; 0 follows new output
+	searching  bool
+	query      string
+	ended      bool
+	err        error
+}
+
+type logsOpenedMsg struct {
+	id       string
+	follower *docker.LogFollower
+	err      error
+}
+
+type logsMsg struct {
+	id    string
+	lines []docker.LogLine
+	done  bool
+	err   error
+}
+
+// openLogs starts following the logs of a container
+func openLogs(client *docker.Client, id string) tea.Cmd {
+	return func() tea.Msg {
+		follower, err := client.FollowLogs(context.Background(), id, logTail)
+		return logsOpenedMsg{id: id, follower: follower, err: err}
+	}
+}
+
+// waitForLogs delivers the next batch of log lines
+func waitForLogs(f *docker.LogFollower, id string) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-f.Lines()
+		if !ok {
+			return logsMsg{id: id, done: true, err: f.Err()}
+		}
+		lines := []docker.LogLine{line}
+		for len(lines) < logBatch {
+			select {
+			case line, ok := <-f.Lines():
+				if !ok {
+					return logsMsg{id: id, lines: lines, done: true, err: f.Err()}
+				}
+				lines = append(lines, line)
+			default:
+				return logsMsg{id: id, lines: lines}
+			}
+		}
+		return logsMsg{id: id, lines: lines}
+	}
+}
+
+// openLogView opens the log viewer for the selected container
+func (m statsModel) openLogView() (statsModel, tea.Cmd) {
+	c, ok := m.selectedContainer()
+	if !ok {
+		return m, nil
+	}
+	m.logs = logView{open: true, id: c.ID, name: c.Name}
+	return m, openLogs(m.client, c.ID)
+}
+
+// closeLogView stops following and returns to the table
+func (m statsModel) closeLogView() statsModel {
+	if m.logs.follower != nil {
+		m.logs.follower.Close()
+	}
+	m.logs = logView{}
+	return m
+}
+
+// updateLogs handles log viewer messages
+func (m statsModel) updateLogs(msg tea.Msg) (statsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case logsOpenedMsg:
+		if !m.logs.open || msg.id != m.logs.id {
+			if msg.follower != nil {
+				msg.follower.Close()
+			}
+			return m, nil
+		}
+		if msg.err != nil {
+			m.logs.err, m.logs.ended = msg.err, true
+			return m, nil
+		}
+		m.logs.follower = msg.follower
+		return m, waitForLogs(msg.follower, msg.id)
+
+	case logsMsg:
+		if !m.logs.open || msg.id != m.logs.id {
+			return m, nil
+		}
+		if m.logs.paused {
+			m.logs.appendPending(msg.lines)
+		} else {
+			m.logs.appendLines(msg.lines)
+		}
+		if msg.done {
+			m.logs.ended, m.logs.err = true, msg.err
+			return m, nil
+		}
+		return m, waitForLogs(m.logs.follower, msg.id)
+	}
+	return m, nil
+}
+
+// appendLines adds lines to the buffer, keeping the view in place when
+// scrolled up
+func (v *logView) appendLines(lines []docker.LogLine) {
+	if v.scroll > 0 {
+		v.scroll += len(lines)
+	}
+	v.lines = capLines(append(v.lines, lines...))
+}
+
+// appendPending holds lines received while paused, dropping the oldest ones
+func (v *logView) appendPending(lines []docker.LogLine) {
+	v.pending = capLines(append(v.pending, lines...))
+}
+
+// capLines drops the oldest lines beyond maxLogLines
+func capLines(lines []docker.LogLine) []docker.LogLine {
+	if over := len(lines) - maxLogLines; over > 0 {
+		return append([]docker.LogLine(nil), lines[over:]...)
+	}
+	return lines
+}
+
+// updateLogKeys handles keys while the log viewer is open
+func (m statsModel) updateLogKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := &m.logs
+	if v.searching {
+		switch msg.Type {
+		case tea.KeyEsc:
+			v.searching, v.query = false, ""
+		case tea.KeyEnter:
+			v.searching = false
+		case tea.KeyBackspace:
+			if r := []rune(v.query); len(r) > 0 {
+				v.query = string(r[:len(r)-1])
+			}
+			m.jumpToMatch(len(v.lines), -1)
+		case tea.KeyRunes, tea.KeySpace:
+			v.query += msg.String()
+			// Incremental: jump to the newest match as the query grows
+			m.jumpToMatch(len(v.lines), -1)
+		case tea.KeyCtrlC:
+			m.quitting = true
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
+	rows := m.logRows()
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+	case "esc", "q":
+		return m.closeLogView(), nil
+	case "t":
+		v.timestamps = !v.timestamps
+	case " ", "p":
+		v.paused = !v.paused
+		if !v.paused {
+			v.appendLines(v.pending)
+			v.pending = nil
+		}
+	case "/":
+		v.searching, v.query = true, ""
+	case "n":
+		m.jumpToMatch(m.logCursor(), -1)
+	case "N":
+		m.jumpToMatch(m.logCursor(), 1)
+	case "up", "k":
+		v.scroll = min(v.scroll+1, max(len(v.lines)-rows, 0))
+	case "down", "j":
+		v.scroll = max(v.scroll-1, 0)
+	case "pgup":
+		v.scroll = min(v.scroll+rows, max(len(v.lines)-rows, 0))
+	case "pgdown":
+		v.scroll = max(v.scroll-rows, 0)
+	case "home", "g":
+		v.scroll = max(len(v.lines)-rows, 0)
+	case "end", "G":
+		v.scroll = 0
+	}
+	return m, nil
+}
+
+// logRows returns how many log lines fit on screen
+func (m statsModel) logRows() int {
+	return max(m.height-3, 1)
+}
+
+// logCursor returns the index of the newest visible line
+func (m statsModel) logCursor() int {
+	return len(m.logs.lines) - 1 - m.logs.scroll
+}
+
+// jumpToMatch scrolls to the nearest line matching the query before
+// (direction -1) or after (direction 1) line from
+func (m *statsModel) jumpToMatch(from, direction int) {
+	v := &m.logs
+	if v.query == "" {
+		return
+	}
+	for i := from + direction; i >= 0 && i < len(v.lines); i += direction {
+		if indexFold(v.lines[i].Text, v.query) >= 0 {
+			v.scroll = len(v.lines) - 1 - i
+			return
+		}
+	}
+}
+
+// renderLogs renders the full-screen log viewer
+func (m statsModel) renderLogs() string {
+	v := m.logs
+
+	status := greenStyle.Render("● following")
+	switch {
+	case v.err != nil:
+		status = redStyle.Render("✕ " + v.err.Error())
+	case v.ended:
+		status = dimStyle.Render("■ stream ended")
+	case v.paused:
+		status = yellowStyle.Render(fmt.Sprintf("⏸ paused (+%d)", len(v.pending)))
+	case v.scroll > 0:
+		status = yellowStyle.Render(fmt.Sprintf("↑ %d lines up", v.scroll))
+	}
+	s := titleStyle.Render(fmt.Sprintf(" 📜 %s ", v.name)) + " " + status + "\n"
+
+	rows := m.logRows()
+	end := len(v.lines) - min(v.scroll, len(v.lines))
+	start := max(end-rows, 0)
+	for _, line := range v.lines[start:end] {
+		s += m.renderLogLine(line, v.query) + "\n"
+	}
+	s += strings.Repeat("\n", rows-(end-start))
+
+	if v.searching {
+		return s + cyanStyle.Render("/") + v.query + "█"
+	}
+	footer := cyanStyle.Render("[/]") + "search " + cyanStyle.Render("[n/N]") + " next/prev " +
+		cyanStyle.Render("[t]") + "imestamps " + cyanStyle.Render("[space]") + " pause " +
+		cyanStyle.Render("[↑↓]") + " scroll " + cyanStyle.Render("[G]") + " follow " + cyanStyle.Render("[esc]") + " back"
+	if v.query != "" {
+		footer += dimStyle.Render("  │  /" + v.query)
+	}
+	return s + footer
+}
+
+// renderLogLine renders one line: stderr in red, matches highlighted
+func (m statsModel) renderLogLine(line docker.LogLine, query string) string {
+	prefix := ""
+	width := m.width
+	if m.logs.timestamps && !line.Time.IsZero() {
+		prefix = dimStyle.Render(line.Time.Local().Format("15:04:05.000")) + " "
+		width -= 13
+	}
+
+	text := []rune(strings.ReplaceAll(line.Text, "\t", "    "))
+	if len(text) > width && width > 1 {
+		text = append(text[:width-1], '…')
+	}
+
+	style := lipgloss.NewStyle()
+	if line.Source == docker.Stderr {
+		style = redStyle
+	}
+	if query == "" {
+		return prefix + style.Render(string(text))
+	}
+
+	// Highlight every case-insensitive occurrence of the query
+	var out string
+	rest := string(text)
+	for {
+		i := indexFold(rest, query)
+		if i < 0 {
+			break
+		}
+		out += style.Render(rest[:i]) + matchStyle.Render(rest[i:i+len(query)])
+		rest = rest[i+len(query):]
+	}
+	return prefix + out + style.Render(rest)
+}
+
+// indexFold returns the byte index of the first case-insensitive
+// occurrence of substr in s, or -1
+func indexFold(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
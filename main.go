@@ -22,11 +22,12 @@
 //
 //	-interval duration    Refresh interval (default 2s)
 //	-all                  Show all containers (including stopped)
+//	-raw-memory           Show raw memory usage including the page cache
 //
 // ## Keyboard Shortcuts
 //
 //	q, Ctrl+C    Quit
-//	r            Force refresh
+//	r, R         Force refresh
 //	c            Sort by CPU
 //	m            Sort by Memory
 //	n            Sort by Name
@@ -40,10 +41,12 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -66,6 +69,18 @@ func main() {
 	simple := flag.Bool("simple", true, "Simple output mode (no TUI, like original bash script)")
 	tui := flag.Bool("tui", false, "Use interactive TUI mode (requires full terminal)")
 	once := flag.Bool("once", false, "Run once and exit (implies -simple)")
+	format := flag.String("format", formatTable, "Output format: table or json (json implies -once), ndjson or csv to stream records every interval, or a Go template")
+	serve := flag.String("serve", "", "Serve Prometheus metrics on this address, e.g. :9487, instead of showing them")
+	otlp := flag.String("otlp", "", "Push metrics to this OTLP/HTTP endpoint every interval, e.g. http://localhost:4318")
+	columnList := flag.String("columns", docker.DefaultColumns, "Comma-separated columns of ndjson and csv output, or all")
+	rawMemory := flag.Bool("raw-memory", false, "Show raw memory usage including the page cache")
+	cpuMode := flag.String("cpu-mode", "host", "CPU percentage relative to one host core (host) or to the container's CPU limit (limit)")
+	readOnly := flag.Bool("read-only", false, "Disable container actions (stop, start, restart, pause, kill, exec)")
+	shellCmd := flag.String("shell", "", "Command run by the exec shell (default: bash if available, else sh)")
+	var filters filterFlag
+	flag.Var(&filters, "filter", "Filter containers by key=value ("+strings.Join(docker.FilterKeys, ", ")+"); repeatable")
+	project := flag.String("project", "", "Only show containers of a Docker Compose project")
+	service := flag.String("service", "", "Only show containers of a Docker Compose service")
 	version := flag.Bool("version", false, "Show version information")
 	help := flag.Bool("help", false, "Show help message")
 	flag.Parse()
@@ -80,6 +95,52 @@ func main() {
 		os.Exit(0)
 	}
 
+	mode, err := docker.ParseCPUMode(*cpuMode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	columns, err := docker.ParseColumns(*columnList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v (available: %s)\n", err, strings.Join(docker.ColumnNames(), ", "))
+		os.Exit(2)
+	}
+
+	var exporter *docker.OTLPExporter
+	if *otlp != "" {
+		headers, err := docker.ParseOTLPHeaders(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+		if err == nil {
+			exporter, err = docker.NewOTLPExporter(*otlp, headers, AppName, AppVersion)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	// Every format but table and json streams records
+	var stream docker.StreamWriter
+	if *format != formatTable && *format != formatJSON {
+		stream, err = docker.NewStreamWriter(os.Stdout, *format, columns)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	if *project != "" {
+		filters = append(filters, docker.ProjectFilter(*project))
+	}
+	if *service != "" {
+		filters = append(filters, docker.ServiceFilter(*service))
+	}
+
+	shell := docker.DefaultShell
+	if *shellCmd != "" {
+		shell = strings.Fields(*shellCmd)
+	}
+
 	// Create Docker client
 	client, err := docker.NewClient()
 	if err != nil {
@@ -88,15 +149,41 @@ func main() {
 		os.Exit(1)
 	}
 	defer client.Close() //nolint:errcheck // intentionally ignoring close error on exit
+	client.SetRawMemory(*rawMemory)
+	client.SetCPUMode(mode)
+	client.SetReadOnly(*readOnly)
+	client.SetFilters(filters)
+
+	if *serve != "" {
+		runServe(client, *showAll, *serve)
+		return
+	}
+	if exporter != nil {
+		runPush(client, *showAll, *interval, exporter, *once)
+		return
+	}
+	if stream != nil {
+		if err := runStream(client, *showAll, *interval, stream, *once); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			client.Close() //nolint:errcheck // exiting anyway; os.Exit skips the deferred close
+			os.Exit(1)
+		}
+		return
+	}
 
 	// Simple mode or once mode (default), TUI only with -tui flag
-	if (*simple && !*tui) || *once {
-		runSimpleMode(client, *showAll, *once, *interval)
+	if *once || *format != formatTable {
+		runOnce(client, *showAll, *format)
+		return
+	}
+	if *simple && !*tui {
+		runSimpleMode(client, *showAll, *interval, shell)
 		return
 	}
 
 	// Create and run UI
 	app := ui.NewApp(client, *interval, *showAll)
+	app.SetShell(shell)
 
 	// Handle graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -113,6 +200,24 @@ func main() {
 	}
 }
 
+// filterFlag collects repeated -filter flags
+type filterFlag []docker.Filter
+
+// String implements flag.Value
+func (f *filterFlag) String() string {
+	return docker.FormatFilters(*f)
+}
+
+// Set implements flag.Value
+func (f *filterFlag) Set(value string) error {
+	filter, err := docker.ParseFilter(value)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, filter)
+	return nil
+}
+
 func printHelp() {
 	fmt.Printf(`%s v%s - Docker Container Statistics Monitor
 
@@ -124,39 +229,98 @@ USAGE:
 OPTIONS:
     -interval duration    Refresh interval (default: 2s)
     -all                  Show all containers (including stopped)
+    -raw-memory           Show raw memory usage including the page cache
+    -cpu-mode mode        CPU percentage relative to one host core (host, default)
+                          or to the container's CPU limit (limit)
+    -read-only            Disable container actions and the exec shell, e.g. on
+                          production hosts
+    -shell command        Command run by the exec shell (default: bash if the
+                          container has it, else sh)
+    -filter key=value     Only show matching containers; repeatable. Keys:
+                          name, label, status, ancestor, network, health
+    -project name         Only show containers of a Docker Compose project
+    -service name         Only show containers of a Docker Compose service
+    -format format        Output format: table (default); json, a versioned
+                          document for scripts that implies -once; or ndjson
+                          or csv, which write one record per container every
+                          interval without the TUI (once with -once); or a
+                          Go template over the container statistics, e.g.
+                          'table {{.Name}}\t{{percent .CPUPercent}}', where
+                          the table prefix aligns columns under a header.
+                          Helpers: bytes, percent, rate, netio, blockio,
+                          memusage, uptime, upper, lower, join, truncate, json
+    -columns list         Comma-separated columns of ndjson and csv output, or
+                          all; names are the JSON field names, plus time
+                          (default: time, id, name, state, cpu_percent,
+                          mem_usage, mem_limit, mem_percent, net/block rates,
+                          pids, error)
+    -serve address        Serve Prometheus metrics on address (e.g. :9487) at
+                          /metrics instead of showing them; statistics are
+                          collected on every scrape, -all includes stopped
+                          containers
+    -otlp url             Push metrics to an OTLP/HTTP collector every interval
+                          (e.g. http://localhost:4318) instead of showing
+                          them, named after the OpenTelemetry container
+                          semantic conventions; headers such as API keys are
+                          read from OTEL_EXPORTER_OTLP_HEADERS
     -version              Show version information
     -help                 Show this help message
 
 KEYBOARD SHORTCUTS:
     q, Ctrl+C    Quit the application
-    r            Force refresh statistics
+    r, R         Force refresh statistics
     c            Sort by CPU usage
     m            Sort by Memory usage
     n            Sort by container Name
+    x            Sort by network throughput
+    b            Sort by disk throughput
+    t            Sort by CPU throttling
+    p            Toggle detail panel (per-core CPU, memory, network, disks)
+    M            Toggle memory breakdown columns (anon, cache, swap, OOM)
+    H            Toggle health, restarts and uptime columns
+    L            Toggle CPU percentage between host cores and the CPU limit
     ↑/↓          Navigate through containers
-    Enter        Show container details
+    Enter        Show container details (Tab/1-4 switch tabs, Esc goes back)
+    /            Filter rows as you type: text, ~regex or label=key[=value]
+                 (Enter keeps the filter, Esc clears it)
+    l            Follow container logs (/ search, n/N matches, t timestamps,
+                 Space pause, G follow, Esc back)
+    S            Stop a running container, start a stopped one
+    T            Restart the selected container
+    P            Pause or unpause the selected container
+    K            Kill the selected container (choose the signal with ←/→)
+    e            Open a shell in the selected container; exit it to return
 
 COLUMNS:
     NAME         Container name
-    CPU%%         CPU usage percentage
-    MEM USAGE    Memory usage (used / limit)
+    HEALTH       Health check status and failing streak
+    RESTARTS     Number of restarts by the restart policy
+    UPTIME       Time since the container started
+    CPU%%         CPU usage percentage (100%% = one host core, or the whole
+                 CPU limit with -cpu-mode limit)
+    THROTTLE     Share of CPU periods throttled by the CPU limit
+    MEM USAGE    Memory usage (used / limit), excluding page cache like 'docker stats'
     MEM%%         Memory usage percentage
     NET I/O      Network input/output
+    NET RATE     Network throughput (bytes/sec received / sent)
     BLOCK I/O    Disk read/write
-    PIDS         Number of processes
+    BLOCK RATE   Disk throughput (bytes/sec read / written)
+    PIDS         Number of processes (and PIDs limit)
     IMAGE SIZE   Size of the container image
 
 EXAMPLES:
     %s                    # Run with default settings
     %s -interval 5s       # Refresh every 5 seconds
     %s -all               # Show all containers
+    %s -project shop      # Watch one Compose stack
+    %s -filter status=exited -filter label=tier=db
 
 REQUIREMENTS:
     - Docker daemon must be running
     - User must have permissions to access Docker socket
       (typically member of 'docker' group or root)
 
-`, AppName, AppVersion, AppName, AppName, AppName, AppName)
+`, AppName, AppVersion, AppName, AppName, AppName, AppName, AppName, AppName)
 }
 
 // Styles for the TUI
@@ -187,11 +351,24 @@ var (
 // Model for bubbletea
 type statsModel struct {
 	client     *docker.Client
-	containers []docker.ContainerStats
+	all        []docker.ContainerStats // Before the interactive filter
+	containers []docker.ContainerStats // Shown rows
 	info       *docker.DockerInfo
+	cache      docker.CacheStats
 	sortField  docker.SortField
 	sortAsc    bool
 	showAll    bool
+	showPanel  bool // detail panel below the table
+	showMemCol bool // optional memory breakdown columns
+	showHealth bool // optional health, restarts and uptime columns
+	detail     detailView
+	logs       logView
+	confirm    *pendingAction // Action awaiting confirmation
+	status     string         // Outcome of the last action
+	shell      []string       // Command run by the exec shell
+	filtering  bool           // The filter prompt is open
+	query      string         // Interactive filter
+	queryErr   error
 	interval   time.Duration
 	width      int
 	height     int
@@ -202,14 +379,16 @@ type statsModel struct {
 }
 
 type tickMsg time.Time
+type changesMsg struct{}
 type containerMsg struct {
 	containers []docker.ContainerStats
 	info       *docker.DockerInfo
+	cache      docker.CacheStats
 	err        error
 }
 
 func (m statsModel) Init() tea.Cmd {
-	return tea.Batch(tickCmd(m.interval), fetchContainers(m.client, m.showAll))
+	return tea.Batch(tickCmd(m.interval), fetchContainers(m.client, m.showAll), waitForChanges(m.client))
 }
 
 func tickCmd(d time.Duration) tea.Cmd {
@@ -218,6 +397,14 @@ func tickCmd(d time.Duration) tea.Cmd {
 	})
 }
 
+// waitForChanges delivers a changesMsg when the container set changes
+func waitForChanges(client *docker.Client) tea.Cmd {
+	return func() tea.Msg {
+		<-client.Changes()
+		return changesMsg{}
+	}
+}
+
 func fetchContainers(client *docker.Client, showAll bool) tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
@@ -226,7 +413,7 @@ func fetchContainers(client *docker.Client, showAll bool) tea.Cmd {
 		if infoErr != nil {
 			info = nil
 		}
-		return containerMsg{containers: containers, info: info, err: err}
+		return containerMsg{containers: containers, info: info, cache: client.InspectCacheStats(), err: err}
 	}
 }
 
@@ -238,7 +425,32 @@ func (m statsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.logs.open {
+			return m.updateLogKeys(msg)
+		}
+		if m.detail.open {
+			return m.updateDetail(msg)
+		}
+		if m.confirm != nil {
+			return m.updateConfirm(msg)
+		}
+		if m.filtering {
+			return m.updateFilterKeys(msg)
+		}
 		switch msg.String() {
+		case "/":
+			m.filtering = true
+		case "esc":
+			m.query = ""
+			m.applyQuery()
+		case "enter":
+			return m.openDetail()
+		case "l":
+			return m.openLogView()
+		case "S", "T", "P", "K":
+			return m.requestAction(msg.String())
+		case "e":
+			return m.openShell()
 		case "q", "ctrl+c":
 			m.quitting = true
 			return m, tea.Quit
@@ -277,6 +489,20 @@ func (m statsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.sortField = docker.SortByImageSize
 				m.sortAsc = false
 			}
+		case "x":
+			if m.sortField == docker.SortByNetRate {
+				m.sortAsc = !m.sortAsc
+			} else {
+				m.sortField = docker.SortByNetRate
+				m.sortAsc = false
+			}
+		case "b":
+			if m.sortField == docker.SortByBlockRate {
+				m.sortAsc = !m.sortAsc
+			} else {
+				m.sortField = docker.SortByBlockRate
+				m.sortAsc = false
+			}
 		case "up", "k":
 			if m.selected > 0 {
 				m.selected--
@@ -287,7 +513,7 @@ func (m statsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "down", "j":
 			if m.selected < len(m.containers)-1 {
 				m.selected++
-				visibleRows := m.height - 10
+				visibleRows := m.height - 10 - m.panelHeight()
 				if visibleRows < 1 {
 					visibleRows = 1
 				}
@@ -306,7 +532,7 @@ func (m statsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.selected >= len(m.containers) {
 				m.selected = len(m.containers) - 1
 			}
-			visibleRows := m.height - 10
+			visibleRows := m.height - 10 - m.panelHeight()
 			if m.selected >= m.scroll+visibleRows {
 				m.scroll = m.selected - visibleRows + 1
 			}
@@ -315,52 +541,101 @@ func (m statsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.scroll = 0
 		case "end":
 			m.selected = len(m.containers) - 1
-			visibleRows := m.height - 10
+			visibleRows := m.height - 10 - m.panelHeight()
 			m.scroll = m.selected - visibleRows + 1
 			if m.scroll < 0 {
 				m.scroll = 0
 			}
-		case "r":
+		case "t":
+			if m.sortField == docker.SortByThrottle {
+				m.sortAsc = !m.sortAsc
+			} else {
+				m.sortField = docker.SortByThrottle
+				m.sortAsc = false
+			}
+		case "p":
+			m.showPanel = !m.showPanel
+		case "M":
+			m.showMemCol = !m.showMemCol
+		case "H":
+			m.showHealth = !m.showHealth
+		case "L":
+			if m.client.CPUMode() == docker.CPUModeLimit {
+				m.client.SetCPUMode(docker.CPUModeHost)
+			} else {
+				m.client.SetCPUMode(docker.CPUModeLimit)
+			}
+			return m, fetchContainers(m.client, m.showAll)
+		case "r", "R":
 			return m, fetchContainers(m.client, m.showAll)
 		}
 		return m, nil
 
 	case tickMsg:
+		if m.detail.open {
+			return m, tea.Batch(tickCmd(m.interval), fetchContainers(m.client, m.showAll), fetchDetail(m.client, m.detail.id))
+		}
 		return m, tea.Batch(tickCmd(m.interval), fetchContainers(m.client, m.showAll))
 
+	case actionMsg:
+		return m.actionResult(msg)
+
+	case shellMsg:
+		return m.shellResult(msg)
+
+	case logsOpenedMsg, logsMsg:
+		return m.updateLogs(msg)
+
+	case detailMsg:
+		if m.detail.open && msg.id == m.detail.id {
+			m.detail.details, m.detail.procs = msg.details, msg.procs
+			m.detail.err, m.detail.procErr = msg.err, msg.procErr
+			m.detail.loaded = true
+		}
+		return m, nil
+
+	case changesMsg:
+		return m, tea.Batch(fetchContainers(m.client, m.showAll), waitForChanges(m.client))
+
 	case containerMsg:
-		m.containers = msg.containers
+		m.all = msg.containers
 		m.info = msg.info
+		m.cache = msg.cache
 		m.err = msg.err
-		docker.SortContainers(m.containers, m.sortField, m.sortAsc)
-		// Keep selected in bounds
-		if m.selected >= len(m.containers) {
-			m.selected = len(m.containers) - 1
-		}
-		if m.selected < 0 {
-			m.selected = 0
-		}
-		// Keep scroll in bounds
-		visibleRows := m.height - 10
-		if visibleRows < 1 {
-			visibleRows = 1
-		}
-		maxScroll := len(m.containers) - visibleRows
-		if maxScroll < 0 {
-			maxScroll = 0
-		}
-		if m.scroll > maxScroll {
-			m.scroll = maxScroll
-		}
-		if m.scroll < 0 {
-			m.scroll = 0
-		}
+		docker.SortContainers(m.all, m.sortField, m.sortAsc)
+		m.applyQuery()
 		return m, nil
 	}
 
 	return m, nil
 }
 
+// clampSelection keeps the selected row and the scroll offset in bounds
+func (m *statsModel) clampSelection() {
+	// Keep selected in bounds
+	if m.selected >= len(m.containers) {
+		m.selected = len(m.containers) - 1
+	}
+	if m.selected < 0 {
+		m.selected = 0
+	}
+	// Keep scroll in bounds
+	visibleRows := m.height - 10 - m.panelHeight()
+	if visibleRows < 1 {
+		visibleRows = 1
+	}
+	maxScroll := len(m.containers) - visibleRows
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	if m.scroll > maxScroll {
+		m.scroll = maxScroll
+	}
+	if m.scroll < 0 {
+		m.scroll = 0
+	}
+}
+
 func (m statsModel) View() string {
 	if m.quitting {
 		return ""
@@ -370,6 +645,13 @@ func (m statsModel) View() string {
 		return "Loading..."
 	}
 
+	if m.logs.open {
+		return m.renderLogs()
+	}
+	if m.detail.open {
+		return m.renderDetail()
+	}
+
 	var s string
 
 	// Header
@@ -380,6 +662,16 @@ func (m statsModel) View() string {
 		header += dimStyle.Render(" │ ") + cyanStyle.Render(fmt.Sprintf("%d imgs", m.info.ImagesTotal))
 	}
 	header += dimStyle.Render(" │ ") + yellowStyle.Render(time.Now().Format("15:04:05"))
+	if filters := m.client.Filters(); len(filters) > 0 {
+		header += dimStyle.Render(" │ ") + cyanStyle.Render("filter: "+docker.FormatFilters(filters))
+	}
+	header += m.filterHeader()
+	var partial *docker.PartialError
+	if errors.As(m.err, &partial) {
+		header += dimStyle.Render(" │ ") + redStyle.Render(fmt.Sprintf("⚠ stats unavailable for %d", len(partial.Failures)))
+	} else if m.err != nil {
+		header += dimStyle.Render(" │ ") + redStyle.Render(fmt.Sprintf("Error: %v", m.err))
+	}
 	s += header + "\n"
 
 	// Sort info and keys
@@ -393,14 +685,21 @@ func (m statsModel) View() string {
 		sortName = "DISK"
 	case docker.SortByImageSize:
 		sortName = "IMG"
+	case docker.SortByNetRate:
+		sortName = "NET/s"
+	case docker.SortByBlockRate:
+		sortName = "DISK/s"
+	case docker.SortByThrottle:
+		sortName = "THROTTLE"
 	}
 	sortDir := "↓"
 	if m.sortAsc {
 		sortDir = "↑"
 	}
 	s += dimStyle.Render("Sort: ") + yellowStyle.Render(sortName) + " " + sortDir
-	s += dimStyle.Render("  │  ") + cyanStyle.Render("[c]") + "pu " + cyanStyle.Render("[m]") + "em " + cyanStyle.Render("[n]") + "ame " + cyanStyle.Render("[d]") + "isk " + cyanStyle.Render("[i]") + "mg"
-	s += dimStyle.Render("  │  ") + cyanStyle.Render("[↑↓]") + "scroll " + cyanStyle.Render("[r]") + "efresh " + redStyle.Render("[q]") + "uit\n\n"
+	s += dimStyle.Render("  │  ") + cyanStyle.Render("[c]") + "pu " + cyanStyle.Render("[m]") + "em " + cyanStyle.Render("[n]") + "ame " + cyanStyle.Render("[d]") + "isk " + cyanStyle.Render("[i]") + "mg " + cyanStyle.Render("[x]") + "net/s " + cyanStyle.Render("[b]") + "disk/s " + cyanStyle.Render("[t]") + "hrottle"
+	s += dimStyle.Render("  │  ") + cyanStyle.Render("[enter]") + "details " + cyanStyle.Render("[l]") + "ogs " + cyanStyle.Render("[p]") + "anel " + cyanStyle.Render("[M]") + "em cols " + cyanStyle.Render("[L]") + "imit CPU"
+	s += dimStyle.Render("  │  ") + cyanStyle.Render("[↑↓]") + "scroll " + cyanStyle.Render("[/]") + "filter " + cyanStyle.Render("[r]") + "efresh " + redStyle.Render("[q]") + "uit\n\n"
 
 	// Calculate dynamic column widths
 	// Find longest container name
@@ -413,7 +712,13 @@ func (m statsModel) View() string {
 	}
 
 	// Calculate remaining width for other columns
-	otherColsWidth := 8 + 8 + 6 + 5 + 8 + 6 + 9 + 9 + 9 + 9 + 9 + 8 + 11 // spaces between columns
+	otherColsWidth := 8 + 8 + 6 + 5 + 8 + 8 + 6 + 9 + 9 + 9 + 9 + 9 + 9 + 9 + 9 + 9 + 9 + 8 + 16 // spaces between columns
+	if m.showMemCol {
+		otherColsWidth += 9 + 9 + 9 + 4 + 4
+	}
+	if m.showHealth {
+		otherColsWidth += 10 + 8 + 7 + 3
+	}
 	maxNameWidth := m.width - otherColsWidth
 	if maxNameWidth < 9 {
 		maxNameWidth = 9
@@ -433,35 +738,63 @@ func (m statsModel) View() string {
 	// Fixed column widths
 	const (
 		colState  = 8
+		colHealth = 10
+		colRst    = 8
+		colUptime = 7
 		colCpuBar = 8
 		colCpuPct = 6
 		colCpuLim = 5
+		colThrot  = 8
 		colMemBar = 8
 		colMemPct = 6
 		colMemUse = 9
 		colNet    = 9
 		colDisk   = 9
+		colRate   = 9
 		colImg    = 8
+		colOOM    = 4
 	)
 
 	// Table header - build manually for exact alignment
 	hdr := fmt.Sprintf("%-*s", colName, "CONTAINER")
 	hdr += fmt.Sprintf(" %-*s", colState, "STATE")
-	hdr += fmt.Sprintf(" %-*s", colCpuBar+1+colCpuPct, "CPU")
+	lifeWidth := 0
+	if m.showHealth {
+		lifeWidth = colHealth + colRst + colUptime + 3
+		hdr += fmt.Sprintf(" %-*s", colHealth, "HEALTH")
+		hdr += fmt.Sprintf(" %-*s", colRst, "RESTARTS")
+		hdr += fmt.Sprintf(" %-*s", colUptime, "UPTIME")
+	}
+	cpuHdr := "CPU"
+	if m.client.CPUMode() == docker.CPUModeLimit {
+		cpuHdr = "CPU/LIMIT"
+	}
+	hdr += fmt.Sprintf(" %-*s", colCpuBar+1+colCpuPct, cpuHdr)
 	hdr += fmt.Sprintf(" %-*s", colCpuLim, "LIMIT")
+	hdr += fmt.Sprintf(" %-*s", colThrot, "THROTTLE")
 	hdr += fmt.Sprintf(" %-*s", colMemBar+1+colMemPct, "MEMORY")
 	hdr += fmt.Sprintf(" %-*s", colMemUse, "MEM USE")
 	hdr += fmt.Sprintf(" %-*s", colMemUse, "MEM LIMIT")
+	if m.showMemCol {
+		hdr += fmt.Sprintf(" %-*s", colMemUse, "ANON")
+		hdr += fmt.Sprintf(" %-*s", colMemUse, "CACHE")
+		hdr += fmt.Sprintf(" %-*s", colMemUse, "SWAP")
+		hdr += fmt.Sprintf(" %-*s", colOOM, "OOM")
+	}
 	hdr += fmt.Sprintf(" %-*s", colNet, "NET RX")
 	hdr += fmt.Sprintf(" %-*s", colNet, "NET TX")
+	hdr += fmt.Sprintf(" %-*s", colRate, "RX/s")
+	hdr += fmt.Sprintf(" %-*s", colRate, "TX/s")
 	hdr += fmt.Sprintf(" %-*s", colDisk, "DISK R")
 	hdr += fmt.Sprintf(" %-*s", colDisk, "DISK W")
+	hdr += fmt.Sprintf(" %-*s", colRate, "READ/s")
+	hdr += fmt.Sprintf(" %-*s", colRate, "WRITE/s")
 	hdr += fmt.Sprintf(" %-*s", colImg, "IMAGE")
 	s += headerStyle.Render(hdr) + "\n"
 	s += dimStyle.Render(repeatStr("─", m.width)) + "\n"
 
 	// Calculate visible rows - maximize to use full terminal height
-	visibleRows := m.height - 8 // Reduce header/footer overhead
+	visibleRows := m.height - 8 - m.panelHeight() // Reduce header/footer overhead
 	if visibleRows < 1 {
 		visibleRows = 1
 	}
@@ -487,6 +820,36 @@ func (m statsModel) View() string {
 			stateStyle = grayStyle
 		}
 
+		// Optional health, restarts and uptime
+		var lifecycle string
+		if m.showHealth {
+			health := "-"
+			if c.Health != "" {
+				health = c.Health
+			}
+			rstStyle := dimStyle
+			if c.RestartCount > 0 {
+				rstStyle = yellowStyle
+			}
+			lifecycle = fmt.Sprintf(" %s", healthStyle(c.Health).Render(fmt.Sprintf("%-*s", colHealth, truncate(health, colHealth))))
+			lifecycle += fmt.Sprintf(" %s", rstStyle.Render(fmt.Sprintf("%-*d", colRst, c.RestartCount)))
+			lifecycle += fmt.Sprintf(" %s", dimStyle.Render(fmt.Sprintf("%-*s", colUptime, docker.FormatUptime(c.Uptime()))))
+		}
+
+		// Stats could not be collected: show n/a and the reason
+		if c.Unavailable() {
+			row := fmt.Sprintf("%-*s", colName, name)
+			row += fmt.Sprintf(" %s", stateStyle.Render(fmt.Sprintf("%-*s", colState, c.State)))
+			row += lifecycle
+			row += " " + redStyle.Render(truncate("n/a: "+c.Error, max(m.width-colName-colState-lifeWidth-2, 8)))
+			if i == m.selected {
+				s += selectedStyle.Render(row) + "\n"
+			} else {
+				s += row + "\n"
+			}
+			continue
+		}
+
 		// CPU bar
 		cpuBar := makeBar(c.CPUPercent, colCpuBar)
 		cpuStyle := cyanStyle
@@ -509,12 +872,27 @@ func (m statsModel) View() string {
 			memStyle = greenStyle
 		}
 
+		// Stale statistics show their last values dimmed
+		thrStyle := throttleStyle(c.ThrottledPercent)
+		netStyle, diskStyle := cyanStyle, blueStyle
+		if c.Stale {
+			cpuBar = dimStyle.Render(repeatStr("░", colCpuBar))
+			memBar = dimStyle.Render(repeatStr("░", colMemBar))
+			cpuStyle, memStyle, thrStyle, netStyle, diskStyle = dimStyle, dimStyle, dimStyle, dimStyle, dimStyle
+		}
+
 		// Format CPU limit
 		cpuLim := "∞"
 		if c.CPULimit > 0 {
 			cpuLim = fmt.Sprintf("%.1f", c.CPULimit)
 		}
 
+		// Format throttling; containers without a CPU quota are never throttled
+		throttle := "-"
+		if c.CPULimit > 0 || c.ThrottledPeriods > 0 {
+			throttle = fmt.Sprintf("%5.1f%%", c.ThrottledPercent)
+		}
+
 		// Format memory usage and limit separately
 		memUse := docker.FormatBytes(c.MemUsage)
 		memLim := docker.FormatBytes(c.MemLimit)
@@ -522,15 +900,31 @@ func (m statsModel) View() string {
 		// Build row with consistent spacing - pad BEFORE color
 		row := fmt.Sprintf("%-*s", colName, name)
 		row += fmt.Sprintf(" %s", stateStyle.Render(fmt.Sprintf("%-*s", colState, c.State)))
+		row += lifecycle
 		row += fmt.Sprintf(" %s %s", cpuBar, cpuStyle.Render(fmt.Sprintf("%*s", colCpuPct, fmt.Sprintf("%5.1f%%", c.CPUPercent))))
 		row += fmt.Sprintf(" %s", dimStyle.Render(fmt.Sprintf("%-*s", colCpuLim, cpuLim)))
+		row += fmt.Sprintf(" %s", thrStyle.Render(fmt.Sprintf("%-*s", colThrot, throttle)))
 		row += fmt.Sprintf(" %s %s", memBar, memStyle.Render(fmt.Sprintf("%*s", colMemPct, fmt.Sprintf("%.1f%%", c.MemPercent))))
 		row += fmt.Sprintf(" %s", dimStyle.Render(fmt.Sprintf("%-*s", colMemUse, memUse)))
 		row += fmt.Sprintf(" %s", dimStyle.Render(fmt.Sprintf("%-*s", colMemUse, memLim)))
-		row += fmt.Sprintf(" %s", cyanStyle.Render(fmt.Sprintf("%-*s", colNet, docker.FormatBytes(c.NetRx))))
-		row += fmt.Sprintf(" %s", cyanStyle.Render(fmt.Sprintf("%-*s", colNet, docker.FormatBytes(c.NetTx))))
-		row += fmt.Sprintf(" %s", blueStyle.Render(fmt.Sprintf("%-*s", colDisk, docker.FormatBytes(c.BlockRead))))
-		row += fmt.Sprintf(" %s", blueStyle.Render(fmt.Sprintf("%-*s", colDisk, docker.FormatBytes(c.BlockWrite))))
+		if m.showMemCol {
+			oomStyle := dimStyle
+			if c.OOMKills > 0 {
+				oomStyle = redStyle
+			}
+			row += fmt.Sprintf(" %s", dimStyle.Render(fmt.Sprintf("%-*s", colMemUse, docker.FormatBytes(c.MemAnon))))
+			row += fmt.Sprintf(" %s", dimStyle.Render(fmt.Sprintf("%-*s", colMemUse, docker.FormatBytes(c.MemFile))))
+			row += fmt.Sprintf(" %s", dimStyle.Render(fmt.Sprintf("%-*s", colMemUse, docker.FormatBytes(c.MemSwap))))
+			row += fmt.Sprintf(" %s", oomStyle.Render(fmt.Sprintf("%-*d", colOOM, c.OOMKills)))
+		}
+		row += fmt.Sprintf(" %s", netStyle.Render(fmt.Sprintf("%-*s", colNet, docker.FormatBytes(c.NetRx))))
+		row += fmt.Sprintf(" %s", netStyle.Render(fmt.Sprintf("%-*s", colNet, docker.FormatBytes(c.NetTx))))
+		row += fmt.Sprintf(" %s", netStyle.Render(fmt.Sprintf("%-*s", colRate, docker.FormatRate(c.NetRxRate))))
+		row += fmt.Sprintf(" %s", netStyle.Render(fmt.Sprintf("%-*s", colRate, docker.FormatRate(c.NetTxRate))))
+		row += fmt.Sprintf(" %s", diskStyle.Render(fmt.Sprintf("%-*s", colDisk, docker.FormatBytes(c.BlockRead))))
+		row += fmt.Sprintf(" %s", diskStyle.Render(fmt.Sprintf("%-*s", colDisk, docker.FormatBytes(c.BlockWrite))))
+		row += fmt.Sprintf(" %s", diskStyle.Render(fmt.Sprintf("%-*s", colRate, docker.FormatRate(c.BlockReadRate))))
+		row += fmt.Sprintf(" %s", diskStyle.Render(fmt.Sprintf("%-*s", colRate, docker.FormatRate(c.BlockWriteRate))))
 		row += fmt.Sprintf(" %s", magentaStyle.Render(fmt.Sprintf("%-*s", colImg, docker.FormatBytesInt64(c.ImageSize))))
 
 		if i == m.selected {
@@ -547,8 +941,15 @@ func (m statsModel) View() string {
 		s += dimStyle.Render(scrollInfo) + "\n"
 	}
 
+	if m.showPanel {
+		s += m.renderPanel()
+	}
+
 	s += dimStyle.Render(repeatStr("═", m.width)) + "\n"
 	s += dimStyle.Render(fmt.Sprintf("  ⟳ Auto-refresh: %s", m.interval.String()))
+	s += dimStyle.Render(fmt.Sprintf("  │  Inspect cache: %d hits / %d misses",
+		m.cache.ContainerHits+m.cache.ImageHits, m.cache.ContainerMisses+m.cache.ImageMisses))
+	s += m.renderActionLine()
 
 	return s
 }
@@ -585,6 +986,36 @@ func makeBar(percent float64, width int) string {
 	return bar
 }
 
+// healthStyle returns the style for a health check status
+func healthStyle(health string) lipgloss.Style {
+	switch health {
+	case "healthy":
+		return greenStyle
+	case "unhealthy":
+		return redStyle
+	case "starting":
+		return yellowStyle
+	default:
+		return dimStyle
+	}
+}
+
+// throttleStyle returns the style for a throttled-periods percentage. Any
+// sustained throttling adds latency, so the thresholds are much lower than
+// for CPU usage.
+func throttleStyle(percent float64) lipgloss.Style {
+	switch {
+	case percent >= 25:
+		return redStyle
+	case percent >= 5:
+		return yellowStyle
+	case percent > 0:
+		return greenStyle
+	default:
+		return dimStyle
+	}
+}
+
 func repeatStr(s string, n int) string {
 	if n <= 0 {
 		return ""
@@ -604,45 +1035,7 @@ func truncate(s string, max int) string {
 }
 
 // runSimpleMode runs the bubbletea TUI
-func runSimpleMode(client *docker.Client, showAll, once bool, interval time.Duration) {
-	if once {
-		// Simple one-shot output without TUI
-		ctx := context.Background()
-		containers, err := client.GetContainerStats(ctx, showAll)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			return
-		}
-		info, infoErr := client.GetDockerInfo(ctx)
-		if infoErr != nil {
-			info = nil
-		}
-
-		fmt.Printf("DOCKER STATS %s | %s", AppVersion, time.Now().Format("15:04:05"))
-		if info != nil {
-			fmt.Printf(" | Docker %s | %d/%d containers | %d images",
-				info.ServerVersion, info.ContainersRunning, info.ContainersTotal, info.ImagesTotal)
-		}
-		fmt.Println()
-		fmt.Printf("%-20s  %-8s  %6s  %6s  %-18s  %-18s  %5s\n",
-			"CONTAINER", "STATE", "CPU%", "MEM%", "NET I/O", "BLOCK I/O", "PID")
-		fmt.Println(repeatStr("-", 100))
-
-		docker.SortContainers(containers, docker.SortByCPU, false)
-		for _, c := range containers {
-			name := c.Name
-			if len(name) > 18 {
-				name = name[:17] + "…"
-			}
-			fmt.Printf("%-20s  %-8s  %5.1f%%  %5.1f%%  %-18s  %-18s  %5d\n",
-				name, c.State, c.CPUPercent, c.MemPercent,
-				truncate(docker.FormatNetIO(c.NetRx, c.NetTx), 18),
-				truncate(docker.FormatBlockIO(c.BlockRead, c.BlockWrite), 18),
-				c.PIDs)
-		}
-		return
-	}
-
+func runSimpleMode(client *docker.Client, showAll bool, interval time.Duration, shell []string) {
 	// Run bubbletea TUI
 	m := statsModel{
 		client:    client,
@@ -650,6 +1043,7 @@ func runSimpleMode(client *docker.Client, showAll, once bool, interval time.Dura
 		interval:  interval,
 		sortField: docker.SortByCPU,
 		sortAsc:   false,
+		shell:     shell,
 	}
 
 	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
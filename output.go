@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tradik/cv-xslt/scripts/tools/stats/internal/docker"
+)
+
+// Output formats of -format
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// runOnce prints the statistics once in the given format and exits
+func runOnce(client *docker.Client, showAll bool, format string) {
+	ctx := context.Background()
+	containers, err := client.GetContainerStats(ctx, showAll)
+	if err != nil && !docker.IsPartial(err) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if format == formatJSON {
+			os.Exit(1)
+		}
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	info, infoErr := client.GetDockerInfo(ctx)
+	if infoErr != nil {
+		info = nil
+	}
+
+	if format == formatJSON {
+		if err := printJSON(client, info, containers); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	printTable(info, containers)
+}
+
+// printJSON writes a versioned snapshot document, sorted by name so runs
+// are easy to diff
+func printJSON(client *docker.Client, info *docker.DockerInfo, containers []docker.ContainerStats) error {
+	docker.SortContainers(containers, docker.SortByName, true)
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(client.NewSnapshot(info, containers, time.Now())); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
+
+// printTable writes a fixed-width table like the original bash script
+func printTable(info *docker.DockerInfo, containers []docker.ContainerStats) {
+	fmt.Printf("DOCKER STATS %s | %s", AppVersion, time.Now().Format("15:04:05"))
+	if info != nil {
+		fmt.Printf(" | Docker %s | %d/%d containers | %d images",
+			info.ServerVersion, info.ContainersRunning, info.ContainersTotal, info.ImagesTotal)
+	}
+	fmt.Println()
+	fmt.Printf("%-20s  %-8s  %-10s  %8s  %7s  %6s  %6s  %-18s  %-18s  %9s\n",
+		"CONTAINER", "STATE", "HEALTH", "RESTARTS", "UPTIME", "CPU%", "MEM%", "NET I/O", "BLOCK I/O", "PIDS")
+	fmt.Println(repeatStr("-", 130))
+
+	docker.SortContainers(containers, docker.SortByCPU, false)
+	for _, c := range containers {
+		name := c.Name
+		if len(name) > 18 {
+			name = name[:17] + "…"
+		}
+		health := "-"
+		if c.Health != "" {
+			health = c.Health
+		}
+		uptime := docker.FormatUptime(c.Uptime())
+		if c.Unavailable() {
+			fmt.Printf("%-20s  %-8s  %-10s  %8d  %7s  %6s  %6s  %s\n",
+				name, c.State, health, c.RestartCount, uptime, "n/a", "n/a", c.Error)
+			continue
+		}
+		pids := fmt.Sprintf("%d", c.PIDs)
+		if c.PIDsLimit > 0 {
+			pids = fmt.Sprintf("%d/%d", c.PIDs, c.PIDsLimit)
+		}
+		// Stale statistics are marked after the last column
+		stale := ""
+		if c.Stale {
+			stale = "  (stale)"
+		}
+		fmt.Printf("%-20s  %-8s  %-10s  %8d  %7s  %5.1f%%  %5.1f%%  %-18s  %-18s  %9s%s\n",
+			name, c.State, health, c.RestartCount, uptime, c.CPUPercent, c.MemPercent,
+			truncate(docker.FormatNetIO(c.NetRx, c.NetTx), 18),
+			truncate(docker.FormatBlockIO(c.BlockRead, c.BlockWrite), 18),
+			pids, stale)
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tradik/cv-xslt/scripts/tools/stats/internal/docker"
+)
+
+// panelLines is the height of the detail panel below the table, including
+// its separator line, without the network interface and block device lines
+const panelLines = 6
+
+// maxPanelInterfaces and maxPanelDevices limit how many network interfaces
+// and block devices the panel lists
+const (
+	maxPanelInterfaces = 4
+	maxPanelDevices    = 4
+)
+
+// panelHeight returns how many lines the detail panel takes
+func (m statsModel) panelHeight() int {
+	if !m.showPanel {
+		return 0
+	}
+	c, ok := m.selectedContainer()
+	if !ok || c.Unavailable() {
+		return panelLines
+	}
+	return panelLines + min(len(c.Networks), maxPanelInterfaces) + min(len(c.Devices), maxPanelDevices)
+}
+
+// selectedContainer returns the container under the cursor, if any
+func (m statsModel) selectedContainer() (docker.ContainerStats, bool) {
+	if m.selected < 0 || m.selected >= len(m.containers) {
+		return docker.ContainerStats{}, false
+	}
+	return m.containers[m.selected], true
+}
+
+// renderPanel renders the detail panel for the selected container
+func (m statsModel) renderPanel() string {
+	c, ok := m.selectedContainer()
+	title := " no container selected "
+	if ok {
+		title = " " + c.Name + " "
+		if c.Stale {
+			title = " " + c.Name + " (stale) "
+		}
+	}
+
+	var s string
+	s += dimStyle.Render("─"+title+repeatStr("─", m.width-len(title)-1)) + "\n"
+	if !ok {
+		return s + strings.Repeat("\n", panelLines-1)
+	}
+	if c.Unavailable() {
+		return s + redStyle.Render("n/a: "+c.Error) + strings.Repeat("\n", panelLines-1)
+	}
+	s += lifecycleLine(c) + "\n"
+	s += cpuLine(c, m.width) + "\n"
+	for _, line := range memoryLines(c) {
+		s += line + "\n"
+	}
+	for i, iface := range c.Networks {
+		if i == maxPanelInterfaces {
+			break
+		}
+		s += interfaceLine(iface) + "\n"
+	}
+	for i, dev := range c.Devices {
+		if i == maxPanelDevices {
+			break
+		}
+		s += deviceLine(dev) + "\n"
+	}
+	return s
+}
+
+// deviceLine describes the I/O of one block device
+func deviceLine(dev docker.DeviceStats) string {
+	label := func(s string) string { return dimStyle.Render(s) }
+
+	s := label("Disk     ") + fmt.Sprintf("%-21s", truncate(dev.Name, 21))
+	s += label(" r ") + fmt.Sprintf("%-10s", docker.FormatRate(dev.ReadRate))
+	s += label(" w ") + fmt.Sprintf("%-10s", docker.FormatRate(dev.WriteRate))
+	s += label(" iops ") + fmt.Sprintf("%.0f/%.0f", dev.ReadIOPS, dev.WriteIOPS)
+	s += label("  total ") + docker.FormatBlockIO(dev.ReadBytes, dev.WriteBytes)
+	s += label("  ops ") + fmt.Sprintf("%d/%d", dev.ReadOps, dev.WriteOps)
+	return s
+}
+
+// interfaceLine describes the traffic of one network interface, with
+// errors and drops highlighted
+func interfaceLine(iface docker.InterfaceStats) string {
+	label := func(s string) string { return dimStyle.Render(s) }
+
+	network := iface.Network
+	if network == "" {
+		network = "?"
+	}
+	s := label("Net      ") + fmt.Sprintf("%-6s %-14s", iface.Name, truncate(network, 14))
+	s += label(" rx ") + fmt.Sprintf("%-10s", docker.FormatRate(iface.RxRate))
+	s += label(" tx ") + fmt.Sprintf("%-10s", docker.FormatRate(iface.TxRate))
+	s += label(" pkts ") + fmt.Sprintf("%d/%d", iface.RxPackets, iface.TxPackets)
+
+	errStyle := dimStyle
+	if iface.RxErrors+iface.TxErrors > 0 {
+		errStyle = redStyle
+	}
+	dropStyle := dimStyle
+	if iface.RxDropped+iface.TxDropped > 0 {
+		dropStyle = redStyle
+	}
+	s += label("  err ") + errStyle.Render(fmt.Sprintf("%d/%d", iface.RxErrors, iface.TxErrors))
+	s += label("  drop ") + dropStyle.Render(fmt.Sprintf("%d/%d", iface.RxDropped, iface.TxDropped))
+	return s
+}
+
+// lifecycleLine shows processes, restarts and how the container last
+// started and exited
+func lifecycleLine(c docker.ContainerStats) string {
+	label := func(s string) string { return dimStyle.Render(s) }
+
+	pids := fmt.Sprintf("%d", c.PIDs)
+	if c.PIDsLimit > 0 {
+		pids += fmt.Sprintf("/%d", c.PIDsLimit)
+	}
+	s := label("PIDs     ") + pids
+	s += label("  restarts ") + fmt.Sprintf("%d (%s)", c.RestartCount, c.RestartPolicy)
+	if c.Health != "" {
+		s += label("  health ") + healthStyle(c.Health).Render(c.Health)
+		if c.HealthFailingStreak > 0 {
+			s += redStyle.Render(fmt.Sprintf(" failing %d", c.HealthFailingStreak))
+		}
+	}
+	if !c.StartedAt.IsZero() {
+		s += label("  started ") + c.StartedAt.Local().Format("2006-01-02 15:04:05")
+	}
+	if c.State != "running" && !c.FinishedAt.IsZero() {
+		s += label("  finished ") + c.FinishedAt.Local().Format("2006-01-02 15:04:05")
+		exitStyle := dimStyle
+		if c.ExitCode != 0 {
+			exitStyle = redStyle
+		}
+		s += label("  exit ") + exitStyle.Render(fmt.Sprintf("%d", c.ExitCode))
+	}
+	if c.OOMKilled {
+		s += "  " + redStyle.Render("OOMKilled")
+	}
+	return s
+}
+
+// coreLevels are the mini bar glyphs for per-core usage, from idle to busy
+var coreLevels = []rune("▁▂▃▄▅▆▇█")
+
+// cpuLine shows the usage of each host core as a mini bar together with the
+// container's cpuset, so single-threaded workloads saturating one core and
+// containers pinned to a hot core stand out
+func cpuLine(c docker.ContainerStats, width int) string {
+	label := func(s string) string { return dimStyle.Render(s) }
+
+	cpuset := "all"
+	if c.CPUSet != "" {
+		cpuset = c.CPUSet
+	}
+	suffix := label("  cpuset ") + cpuset
+
+	if len(c.PerCPUPercent) == 0 {
+		return label("Cores    ") + dimStyle.Render("per-core usage not reported (cgroup v2)") + suffix
+	}
+
+	hottest := 0
+	for i, p := range c.PerCPUPercent {
+		if p > c.PerCPUPercent[hottest] {
+			hottest = i
+		}
+	}
+	suffix = label(fmt.Sprintf("  max %.0f%% on cpu%d", c.PerCPUPercent[hottest], hottest)) + suffix
+
+	// Leave room for the label and the suffix
+	cores := c.PerCPUPercent
+	if room := width - 50; room > 0 && len(cores) > room {
+		cores = cores[:room]
+	}
+
+	var bars string
+	for _, p := range cores {
+		level := int(p / 100 * float64(len(coreLevels)-1))
+		level = min(max(level, 0), len(coreLevels)-1)
+		style := cyanStyle
+		switch {
+		case p >= 90:
+			style = redStyle
+		case p >= 70:
+			style = yellowStyle
+		case p >= 40:
+			style = greenStyle
+		}
+		bars += style.Render(string(coreLevels[level]))
+	}
+	return label("Cores    ") + bars + suffix
+}
+
+// memoryLines describes the memory usage of a container in detail, so a
+// leaking process can be told apart from a container that caches files
+func memoryLines(c docker.ContainerStats) []string {
+	label := func(s string) string { return dimStyle.Render(s) }
+
+	usage := label("Memory   ") + fmt.Sprintf("%s / %s (%.1f%%)",
+		docker.FormatBytes(c.MemUsage), docker.FormatBytes(c.MemLimit), c.MemPercent)
+	usage += label("  raw ") + docker.FormatBytes(c.MemUsageRaw)
+	if c.MemMaxUsage > 0 {
+		usage += label("  peak ") + docker.FormatBytes(c.MemMaxUsage)
+	}
+
+	parts := label("         anon ") + docker.FormatBytes(c.MemAnon)
+	parts += label("  cache ") + docker.FormatBytes(c.MemFile)
+	parts += label("  shmem ") + docker.FormatBytes(c.MemShmem)
+	parts += label("  swap ") + docker.FormatBytes(c.MemSwap)
+
+	pressureStyle := dimStyle
+	if c.MemFailcnt > 0 || c.OOMKills > 0 {
+		pressureStyle = redStyle
+	}
+	pressure := label("         failcnt ") + pressureStyle.Render(fmt.Sprintf("%d", c.MemFailcnt))
+	pressure += label("  OOM kills ") + pressureStyle.Render(fmt.Sprintf("%d", c.OOMKills))
+
+	return []string{usage, parts, pressure}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tradik/cv-xslt/scripts/tools/stats/internal/docker"
+)
+
+// runPush pushes metrics to an OTLP endpoint every interval until
+// interrupted, or once with once set. Failed pushes are reported and
+// retried on the next interval.
+func runPush(client *docker.Client, showAll bool, interval time.Duration, exporter *docker.OTLPExporter, once bool) {
+	fmt.Fprintf(os.Stderr, "Pushing metrics to %s every %s\n", exporter.URL(), interval)
+
+	var info *docker.DockerInfo
+	forEachInterval(interval, once, func(ctx context.Context) error { //nolint:errcheck // the callback reports its errors and never fails
+		containers, err := client.GetContainerStats(ctx, showAll)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil && !docker.IsPartial(err):
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return nil
+		}
+		// The host attributes rarely change; fetch them until known
+		if info == nil {
+			info, err = client.GetDockerInfo(ctx)
+			if err != nil && ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+		}
+		if err := exporter.Export(ctx, info, containers, time.Now()); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		return nil
+	})
+}
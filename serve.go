@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/tradik/cv-xslt/scripts/tools/stats/internal/docker"
+)
+
+const (
+	// scrapeTimeout bounds how long one scrape may query the daemon
+	scrapeTimeout = 10 * time.Second
+	// shutdownTimeout bounds how long running scrapes may finish on exit
+	shutdownTimeout = 5 * time.Second
+)
+
+// metricsHandler serves the container statistics in the Prometheus text
+// format, collecting them on every scrape
+type metricsHandler struct {
+	client  *docker.Client
+	showAll bool
+	mu      sync.Mutex // Serializes scrapes so they share one set of stats streams
+}
+
+// ServeHTTP implements http.Handler
+func (h *metricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), scrapeTimeout)
+	defer cancel()
+
+	h.mu.Lock()
+	containers, err := h.client.GetContainerStats(ctx, h.showAll)
+	info, infoErr := h.client.GetDockerInfo(ctx)
+	h.mu.Unlock()
+	if err != nil && !docker.IsPartial(err) {
+		// A failed scrape marks the target down rather than every container idle
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if infoErr != nil {
+		info = nil
+	}
+
+	docker.SortContainers(containers, docker.SortByName, true)
+	w.Header().Set("Content-Type", docker.PrometheusContentType)
+	docker.WritePrometheus(w, docker.CollectMetrics(info, containers)) //nolint:errcheck // the scraper went away
+}
+
+// indexPage links to the metrics from the root path
+const indexPage = `<html><head><title>%s</title></head><body><h1>%s</h1><p><a href="/metrics">Metrics</a></p></body></html>`
+
+// runServe exposes Prometheus metrics on addr until interrupted
+func runServe(client *docker.Client, showAll bool, addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", &metricsHandler{client: client, showAll: showAll})
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, indexPage, AppName, AppName)
+	})
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: scrapeTimeout}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx) //nolint:errcheck // exiting anyway
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving metrics on http://%s/metrics\n", addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
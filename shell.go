@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tradik/cv-xslt/scripts/tools/stats/internal/docker"
+)
+
+// shellCommand runs an interactive exec session while the TUI is suspended
+type shellCommand struct {
+	client *docker.Client
+	id     string
+	name   string
+	cmd    []string
+	stdin  io.Reader
+	stdout io.Writer
+	code   int
+}
+
+type shellMsg struct {
+	name string
+	code int
+	err  error
+}
+
+// Run implements tea.ExecCommand
+func (s *shellCommand) Run() error {
+	fmt.Fprintf(s.stdout, "Connected to %s, exit the shell to return.\r\n", s.name)
+	code, err := s.client.ExecTerminal(context.Background(), s.id, s.cmd, s.stdin, s.stdout)
+	s.code = code
+	return err
+}
+
+// SetStdin implements tea.ExecCommand
+func (s *shellCommand) SetStdin(r io.Reader) { s.stdin = r }
+
+// SetStdout implements tea.ExecCommand
+func (s *shellCommand) SetStdout(w io.Writer) { s.stdout = w }
+
+// SetStderr implements tea.ExecCommand; a TTY session has no separate
+// error stream
+func (s *shellCommand) SetStderr(io.Writer) {}
+
+// openShell suspends the TUI and opens a shell in the selected container
+func (m statsModel) openShell() (tea.Model, tea.Cmd) {
+	c, ok := m.selectedContainer()
+	if !ok {
+		return m, nil
+	}
+	if m.client.ReadOnly() {
+		m.status = redStyle.Render("✕ " + docker.ErrReadOnly.Error())
+		return m, nil
+	}
+
+	cmd := &shellCommand{client: m.client, id: c.ID, name: c.Name, cmd: m.shell, stdin: os.Stdin, stdout: os.Stdout}
+	return m, tea.Exec(cmd, func(err error) tea.Msg {
+		return shellMsg{name: c.Name, code: cmd.code, err: err}
+	})
+}
+
+// shellResult records how the shell session ended in the status line
+func (m statsModel) shellResult(msg shellMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.err != nil:
+		m.status = redStyle.Render("✕ " + msg.err.Error())
+	case msg.code != 0:
+		m.status = yellowStyle.Render(fmt.Sprintf("shell in %s exited with code %d", msg.name, msg.code))
+	default:
+		m.status = dimStyle.Render(fmt.Sprintf("shell in %s exited", msg.name))
+	}
+	return m, fetchContainers(m.client, m.showAll)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/tradik/cv-xslt/scripts/tools/stats/internal/docker"
+)
+
+// forEachInterval calls f now and then every interval until interrupted,
+// or only once with once set. An error from f stops the loop and is returned.
+func forEachInterval(interval time.Duration, once bool, f func(ctx context.Context) error) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := f(ctx); err != nil {
+			return err
+		}
+		if once {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// runStream writes one record per container every interval until
+// interrupted, or once with once set. It runs without a terminal UI so the
+// output can be piped. It returns the error of a failed write, such as a
+// closed pipe.
+func runStream(client *docker.Client, showAll bool, interval time.Duration, w docker.StreamWriter, once bool) error {
+	return forEachInterval(interval, once, func(ctx context.Context) error {
+		containers, err := client.GetContainerStats(ctx, showAll)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil && !docker.IsPartial(err):
+			// Keep streaming; the daemon may come back
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return nil
+		}
+		// Unavailable containers are reported in their error column
+		docker.SortContainers(containers, docker.SortByName, true)
+		return w.Write(time.Now(), containers)
+	})
+}